@@ -0,0 +1,215 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wavetermdev/waveterm/pkg/waveobj"
+	"github.com/wavetermdev/waveterm/pkg/wshrpc"
+	"github.com/wavetermdev/waveterm/pkg/wshrpc/wshclient"
+)
+
+var debugTermCategoryColors = map[string]string{
+	"text": "\x1b[32m", // green
+	"csi":  "\x1b[36m", // cyan
+	"osc":  "\x1b[35m", // magenta
+	"ctl":  "\x1b[33m", // yellow
+}
+
+// debugTermStreamDecoder keeps formatDebugTermDecode's state machine alive across
+// successive chunks of a live stream by holding back an incomplete trailing escape
+// sequence until the bytes that complete it arrive.
+type debugTermStreamDecoder struct {
+	carry []byte
+}
+
+// Feed decodes as much of carry+chunk as forms complete events, buffering any
+// incomplete trailing escape sequence for the next call.
+func (d *debugTermStreamDecoder) Feed(chunk []byte) string {
+	combined := append(d.carry, chunk...)
+	safe, carry := splitDebugTermIncomplete(combined)
+	d.carry = append([]byte(nil), carry...)
+	if len(safe) == 0 {
+		return ""
+	}
+	return formatDebugTermDecode(safe)
+}
+
+// splitDebugTermIncomplete splits data into a decodable prefix and a trailing partial
+// escape sequence (if the buffer ends mid-sequence), so a CSI/OSC/DCS/APC/PM split
+// across a read boundary isn't mis-decoded as a truncated, stray sequence.
+func splitDebugTermIncomplete(data []byte) (safe, carry []byte) {
+	idx := bytes.LastIndexByte(data, 0x1b)
+	if idx < 0 {
+		return data, nil
+	}
+	if idx == len(data)-1 {
+		return data[:idx], data[idx:]
+	}
+	switch data[idx+1] {
+	case '[':
+		seq, end := consumeDebugTermCSI(data, idx)
+		if end == len(data) && !isCompleteDebugTermCSI(seq) {
+			return data[:idx], data[idx:]
+		}
+	case ']':
+		seq, end := consumeDebugTermOSC(data, idx)
+		if end == len(data) && !isCompleteDebugTermST(seq) {
+			return data[:idx], data[idx:]
+		}
+	case 'P', '^', '_':
+		seq, end := consumeDebugTermST(data, idx)
+		if end == len(data) && !isCompleteDebugTermST(seq) {
+			return data[:idx], data[idx:]
+		}
+	}
+	return data, nil
+}
+
+func isCompleteDebugTermCSI(seq []byte) bool {
+	return len(seq) >= 3 && seq[len(seq)-1] >= 0x40 && seq[len(seq)-1] <= 0x7e
+}
+
+func isCompleteDebugTermST(seq []byte) bool {
+	if len(seq) == 0 {
+		return false
+	}
+	if seq[len(seq)-1] == 0x07 {
+		return true
+	}
+	return len(seq) >= 2 && seq[len(seq)-2] == 0x1b && seq[len(seq)-1] == '\\'
+}
+
+// parseDebugTermTimeFilters converts --since/--until flag values (RFC3339 timestamps,
+// or "-<duration>" for a time relative to now, e.g. "-5m") into unix-millisecond bounds.
+// An empty string maps to 0 (no bound).
+func parseDebugTermTimeFilters(since, until string) (sinceMs, untilMs int64, err error) {
+	parseOne := func(s string) (int64, error) {
+		if s == "" {
+			return 0, nil
+		}
+		if strings.HasPrefix(s, "-") {
+			d, err := time.ParseDuration(s[1:])
+			if err != nil {
+				return 0, fmt.Errorf("invalid relative time %q: %w", s, err)
+			}
+			return time.Now().Add(-d).UnixMilli(), nil
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q (expected RFC3339 or -<duration>): %w", s, err)
+		}
+		return t.UnixMilli(), nil
+	}
+	if sinceMs, err = parseOne(since); err != nil {
+		return 0, 0, err
+	}
+	if untilMs, err = parseOne(until); err != nil {
+		return 0, 0, err
+	}
+	return sinceMs, untilMs, nil
+}
+
+// parseDebugTermFilterSet parses a comma-separated --filter value into a set of
+// categories to keep. An empty string means "keep everything".
+func parseDebugTermFilterSet(filter string) map[string]bool {
+	if strings.TrimSpace(filter) == "" {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, part := range strings.Split(filter, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			set[part] = true
+		}
+	}
+	return set
+}
+
+// categoryForDebugTermLine classifies a single line of formatDebugTermDecode output
+// into one of the --filter categories (csi, osc, text, ctl), or "other".
+func categoryForDebugTermLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "TXT "):
+		return "text"
+	case strings.HasPrefix(line, "CSI "), strings.HasPrefix(line, "DEC "):
+		return "csi"
+	case strings.HasPrefix(line, "OSC "), strings.HasPrefix(line, "DCS "), strings.HasPrefix(line, "APC "), strings.HasPrefix(line, "PM "):
+		return "osc"
+	case strings.HasPrefix(line, "BEL"), strings.HasPrefix(line, "CTL "), strings.HasPrefix(line, "ESC"):
+		return "ctl"
+	default:
+		return "other"
+	}
+}
+
+// filterAndColorDebugTermLines drops lines whose category isn't in filterSet (when
+// non-empty) and, if color is set, ANSI-highlights each line's category prefix.
+func filterAndColorDebugTermLines(output string, filterSet map[string]bool, color bool) string {
+	if output == "" {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		cat := categoryForDebugTermLine(line)
+		if len(filterSet) > 0 && !filterSet[cat] {
+			continue
+		}
+		if color {
+			line = colorizeDebugTermLine(cat, line)
+		}
+		kept = append(kept, line)
+	}
+	if len(kept) == 0 {
+		return ""
+	}
+	return strings.Join(kept, "\n") + "\n"
+}
+
+func colorizeDebugTermLine(category, line string) string {
+	color, ok := debugTermCategoryColors[category]
+	if !ok {
+		return line
+	}
+	prefix, rest, found := strings.Cut(line, " ")
+	if !found {
+		return color + line + "\x1b[0m"
+	}
+	return color + prefix + "\x1b[0m " + rest
+}
+
+func debugTermRunFollow(fullORef waveobj.ORef) error {
+	sinceMs, untilMs, err := parseDebugTermTimeFilters(debugTermSince, debugTermUntil)
+	if err != nil {
+		return err
+	}
+	filterSet := parseDebugTermFilterSet(debugTermFilter)
+	events, err := wshclient.DebugTermFollowCommand(RpcClient, wshrpc.CommandDebugTermFollowData{
+		BlockId: fullORef.OID,
+		SinceTs: sinceMs,
+		UntilTs: untilMs,
+	}, &wshrpc.RpcOpts{Timeout: 0})
+	if err != nil {
+		return fmt.Errorf("subscribing to terminal output: %w", err)
+	}
+	dec := &debugTermStreamDecoder{}
+	for event := range events {
+		chunk, err := base64.StdEncoding.DecodeString(event.Data64)
+		if err != nil {
+			continue
+		}
+		output := dec.Feed(chunk)
+		if output == "" {
+			continue
+		}
+		WriteStdout("%s", filterAndColorDebugTermLines(output, filterSet, debugTermColor))
+	}
+	return nil
+}
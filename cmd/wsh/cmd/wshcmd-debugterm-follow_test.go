@@ -0,0 +1,106 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDebugTermStreamDecoderSplitsAcrossChunks(t *testing.T) {
+	dec := &debugTermStreamDecoder{}
+	// "\x1b[31m" (set red) split across two Feed calls mid-sequence.
+	out1 := dec.Feed([]byte("hi\x1b[3"))
+	if !strings.Contains(out1, `TXT "hi"`) {
+		t.Fatalf("expected text before the split CSI to decode immediately, got %q", out1)
+	}
+	if strings.Contains(out1, "CSI") {
+		t.Fatalf("incomplete CSI should not be emitted yet, got %q", out1)
+	}
+	out2 := dec.Feed([]byte("1mred"))
+	if !strings.Contains(out2, "CSI m 31") {
+		t.Fatalf("expected completed CSI after second chunk, got %q", out2)
+	}
+	if !strings.Contains(out2, `TXT "red"`) {
+		t.Fatalf("expected trailing text after the CSI, got %q", out2)
+	}
+}
+
+func TestSplitDebugTermIncompleteCompleteTrailingCSI(t *testing.T) {
+	safe, carry := splitDebugTermIncomplete([]byte("hello\x1b[31m"))
+	if string(safe) != "hello\x1b[31m" {
+		t.Fatalf("expected the full complete sequence to be safe, got %q", safe)
+	}
+	if len(carry) != 0 {
+		t.Fatalf("expected no carry, got %q", carry)
+	}
+}
+
+func TestSplitDebugTermIncompleteBareESC(t *testing.T) {
+	safe, carry := splitDebugTermIncomplete([]byte("hello\x1b"))
+	if string(safe) != "hello" {
+		t.Fatalf("unexpected safe prefix: %q", safe)
+	}
+	if string(carry) != "\x1b" {
+		t.Fatalf("expected bare ESC to carry over, got %q", carry)
+	}
+}
+
+func TestParseDebugTermFilterSet(t *testing.T) {
+	if set := parseDebugTermFilterSet(""); set != nil {
+		t.Fatalf("expected nil (no filtering) for empty filter, got %v", set)
+	}
+	set := parseDebugTermFilterSet("csi, text")
+	if !set["csi"] || !set["text"] || set["osc"] {
+		t.Fatalf("unexpected filter set: %v", set)
+	}
+}
+
+func TestCategoryForDebugTermLine(t *testing.T) {
+	cases := map[string]string{
+		`TXT "hi"`:    "text",
+		"CSI m 31":    "csi",
+		"DEC SET 25":  "csi",
+		"OSC 0 \"t\"": "osc",
+		"DCS SIXEL":   "osc",
+		"BEL":         "ctl",
+		"CTL 0x00":    "ctl",
+	}
+	for line, want := range cases {
+		if got := categoryForDebugTermLine(line); got != want {
+			t.Fatalf("categoryForDebugTermLine(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestFilterAndColorDebugTermLines(t *testing.T) {
+	output := "TXT \"hi\"\nCSI m 31\nBEL\n"
+	filtered := filterAndColorDebugTermLines(output, parseDebugTermFilterSet("text"), false)
+	if filtered != "TXT \"hi\"\n" {
+		t.Fatalf("unexpected filtered output: %q", filtered)
+	}
+	colored := filterAndColorDebugTermLines("TXT \"hi\"\n", nil, true)
+	if !strings.HasPrefix(colored, "\x1b[32mTXT\x1b[0m ") {
+		t.Fatalf("expected TXT prefix to be colorized, got %q", colored)
+	}
+}
+
+func TestParseDebugTermTimeFilters(t *testing.T) {
+	sinceMs, untilMs, err := parseDebugTermTimeFilters("", "")
+	if err != nil || sinceMs != 0 || untilMs != 0 {
+		t.Fatalf("expected zero bounds for empty input, got (%d, %d, %v)", sinceMs, untilMs, err)
+	}
+	sinceMs, _, err = parseDebugTermTimeFilters("-5m", "")
+	if err != nil {
+		t.Fatalf("parseDebugTermTimeFilters() error: %v", err)
+	}
+	wantMs := time.Now().Add(-5 * time.Minute).UnixMilli()
+	if diff := wantMs - sinceMs; diff < -1000 || diff > 1000 {
+		t.Fatalf("expected ~5m ago, got %d vs want ~%d", sinceMs, wantMs)
+	}
+	if _, _, err := parseDebugTermTimeFilters("not-a-time", ""); err == nil {
+		t.Fatalf("expected error for invalid time")
+	}
+}
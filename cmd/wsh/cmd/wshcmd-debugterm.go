@@ -4,6 +4,8 @@
 package cmd
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -19,6 +21,10 @@ import (
 	"github.com/wavetermdev/waveterm/pkg/wshrpc/wshclient"
 )
 
+// debugTermLargePayloadThreshold caps how many raw bytes of an unrecognized DCS/APC payload
+// we'll dump as a quoted string before falling back to a byte-count + hash summary.
+const debugTermLargePayloadThreshold = 256
+
 const (
 	DebugTermModeHex    = "hex"
 	DebugTermModeDecode = "decode"
@@ -34,10 +40,15 @@ var debugTermCmd = &cobra.Command{
 }
 
 var (
-	debugTermSize  int64
-	debugTermMode  string
-	debugTermStdin bool
-	debugTermInput string
+	debugTermSize   int64
+	debugTermMode   string
+	debugTermStdin  bool
+	debugTermInput  string
+	debugTermFollow bool
+	debugTermSince  string
+	debugTermUntil  string
+	debugTermFilter string
+	debugTermColor  bool
 )
 
 func init() {
@@ -46,6 +57,11 @@ func init() {
 	debugTermCmd.Flags().StringVar(&debugTermMode, "mode", DebugTermModeHex, "output mode: hex or decode")
 	debugTermCmd.Flags().BoolVar(&debugTermStdin, "stdin", false, "read input from stdin instead of rpc call")
 	debugTermCmd.Flags().StringVar(&debugTermInput, "input", "", "read input from file instead of rpc call")
+	debugTermCmd.Flags().BoolVarP(&debugTermFollow, "follow", "f", false, "stream live terminal output instead of a single snapshot")
+	debugTermCmd.Flags().StringVar(&debugTermSince, "since", "", "only show events at or after this time (RFC3339 or relative like -5m)")
+	debugTermCmd.Flags().StringVar(&debugTermUntil, "until", "", "stop following at this time (RFC3339 or relative like -5m)")
+	debugTermCmd.Flags().StringVar(&debugTermFilter, "filter", "", "comma-separated categories to show: csi,osc,text,ctl (default: all)")
+	debugTermCmd.Flags().BoolVar(&debugTermColor, "color", false, "ANSI-highlight the category prefix of each decoded line")
 }
 
 func debugTermRun(cmd *cobra.Command, args []string) (rtnErr error) {
@@ -88,6 +104,16 @@ func debugTermRun(cmd *cobra.Command, args []string) (rtnErr error) {
 		}
 		return nil
 	}
+	if debugTermFollow {
+		if mode != DebugTermModeDecode {
+			return fmt.Errorf("--follow requires --mode=decode")
+		}
+		fullORef, err := resolveBlockArg()
+		if err != nil {
+			return err
+		}
+		return debugTermRunFollow(fullORef)
+	}
 	if debugTermSize <= 0 {
 		return fmt.Errorf("size must be greater than 0")
 	}
@@ -267,7 +293,7 @@ func formatDebugTermDecode(data []byte) string {
 			case 'P':
 				flushText()
 				seq, end := consumeDebugTermST(data, i)
-				lines = append(lines, "DCS "+strconv.QuoteToASCII(string(seq)))
+				lines = append(lines, formatDebugTermDCSLine(seq))
 				i = end
 			case '^':
 				flushText()
@@ -277,7 +303,7 @@ func formatDebugTermDecode(data []byte) string {
 			case '_':
 				flushText()
 				seq, end := consumeDebugTermST(data, i)
-				lines = append(lines, "APC "+strconv.QuoteToASCII(string(seq)))
+				lines = append(lines, formatDebugTermAPCLine(seq))
 				i = end
 			default:
 				flushText()
@@ -491,6 +517,9 @@ func formatDebugTermOSCLine(seq []byte) string {
 	if idx := strings.IndexByte(inner, ';'); idx >= 0 {
 		code := inner[:idx]
 		data := inner[idx+1:]
+		if code == "1337" && strings.HasPrefix(data, "File=") {
+			return formatDebugTermITerm2FileLine(data)
+		}
 		return "OSC " + code + " " + strconv.QuoteToASCII(data)
 	}
 	return "OSC " + strconv.QuoteToASCII(inner)
@@ -551,3 +580,141 @@ func consumeDebugTermText(data []byte, i int) (start, end int) {
 	}
 	return start, i
 }
+
+// trimDebugTermIntroducer strips the leading ESC+introducer byte and a trailing ST
+// (ESC \) from a DCS/APC/PM sequence produced by consumeDebugTermST.
+func trimDebugTermIntroducer(seq []byte) []byte {
+	inner := seq[2:]
+	return bytes.TrimSuffix(inner, []byte("\x1b\\"))
+}
+
+// elideDebugTermPayload summarizes a binary payload as a byte count plus a short sha256
+// prefix, so dumping megabytes of image data into debugterm output stays readable.
+func elideDebugTermPayload(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return fmt.Sprintf("%d bytes sha256:%s", len(payload), hex.EncodeToString(sum[:])[:8])
+}
+
+// parseSixelDCS splits a sixel DCS body ("P1;P2;P3q<data>") into its numeric params and
+// raw sixel data, returning ok=false if it doesn't look like sixel at all.
+func parseSixelDCS(inner []byte) (params string, payload []byte, ok bool) {
+	i := 0
+	for i < len(inner) && (inner[i] == ';' || (inner[i] >= '0' && inner[i] <= '9')) {
+		i++
+	}
+	if i == 0 || i >= len(inner) || inner[i] != 'q' {
+		return "", nil, false
+	}
+	return string(inner[:i]), inner[i+1:], true
+}
+
+// sixelGeometry estimates the pixel dimensions of a sixel image by counting '-' row
+// separators (each sixel row is 6 pixels tall) and the longest run of sixel data bytes
+// (each byte is one pixel column) in any single row.
+func sixelGeometry(payload []byte) (rows, maxRunWidth int) {
+	rows = 1
+	curRun := 0
+	for _, b := range payload {
+		switch {
+		case b == '-':
+			rows++
+			curRun = 0
+		case b == '$':
+			curRun = 0
+		case b >= '?' && b <= '~':
+			curRun++
+			if curRun > maxRunWidth {
+				maxRunWidth = curRun
+			}
+		default:
+			curRun = 0
+		}
+	}
+	return rows, maxRunWidth
+}
+
+func formatDebugTermDCSLine(seq []byte) string {
+	inner := trimDebugTermIntroducer(seq)
+	if params, payload, ok := parseSixelDCS(inner); ok {
+		rows, width := sixelGeometry(payload)
+		return fmt.Sprintf("DCS SIXEL params=%s pixels≈%dx%d", params, width, rows*6)
+	}
+	if len(inner) > debugTermLargePayloadThreshold {
+		return "DCS " + elideDebugTermPayload(inner)
+	}
+	return "DCS " + strconv.QuoteToASCII(string(inner))
+}
+
+// parseKittyAPC splits a Kitty graphics APC body ("G<k=v,k=v,...>;<payload>") into its
+// key/value header and raw (base64) payload, returning ok=false if it isn't a Kitty
+// graphics command.
+func parseKittyAPC(inner []byte) (kv map[string]string, payload []byte, ok bool) {
+	if len(inner) == 0 || inner[0] != 'G' {
+		return nil, nil, false
+	}
+	rest := inner[1:]
+	header := rest
+	if idx := bytes.IndexByte(rest, ';'); idx >= 0 {
+		header = rest[:idx]
+		payload = rest[idx+1:]
+	}
+	kv = make(map[string]string)
+	for _, part := range strings.Split(string(header), ",") {
+		k, v, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		kv[k] = v
+	}
+	return kv, payload, true
+}
+
+func formatDebugTermAPCLine(seq []byte) string {
+	inner := trimDebugTermIntroducer(seq)
+	if kv, payload, ok := parseKittyAPC(inner); ok {
+		var parts []string
+		if a, ok := kv["a"]; ok {
+			parts = append(parts, "a="+a)
+		}
+		if f, ok := kv["f"]; ok {
+			parts = append(parts, "f="+f)
+		}
+		if s, ok := kv["s"]; ok {
+			if v, ok := kv["v"]; ok {
+				parts = append(parts, "s="+s+"x"+v)
+			} else {
+				parts = append(parts, "s="+s)
+			}
+		}
+		parts = append(parts, fmt.Sprintf("payload=<%d bytes base64>", len(payload)))
+		return "APC KITTY " + strings.Join(parts, " ")
+	}
+	if len(inner) > debugTermLargePayloadThreshold {
+		return "APC " + elideDebugTermPayload(inner)
+	}
+	return "APC " + strconv.QuoteToASCII(string(inner))
+}
+
+// formatDebugTermITerm2FileLine formats an iTerm2 inline-image OSC 1337 payload
+// ("File=name=...;size=...;inline=1:<base64>") without echoing the image bytes.
+func formatDebugTermITerm2FileLine(data string) string {
+	data = strings.TrimPrefix(data, "File=")
+	params, payload64, _ := strings.Cut(data, ":")
+	kv := make(map[string]string)
+	for _, part := range strings.Split(params, ";") {
+		k, v, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		kv[k] = v
+	}
+	name := kv["name"]
+	if decoded, err := base64.StdEncoding.DecodeString(name); err == nil {
+		name = string(decoded)
+	}
+	byteCount := len(payload64)
+	if decoded, err := base64.StdEncoding.DecodeString(payload64); err == nil {
+		byteCount = len(decoded)
+	}
+	return fmt.Sprintf("OSC 1337 iTerm2-File name=%s size=%s bytes=%d", name, kv["size"], byteCount)
+}
@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"encoding/base64"
 	"strings"
 	"testing"
 )
@@ -98,3 +99,41 @@ func TestParseDebugTermStdinDataRaw(t *testing.T) {
 		t.Fatalf("expected raw passthrough, got %q", data)
 	}
 }
+
+func TestFormatDebugTermDecodeSixel(t *testing.T) {
+	// two sixel rows; the second row has the longer run of sixel data bytes.
+	sixel := []byte("\x1bP1;2;3q" + "???" + "-" + "?????" + "\x1b\\")
+	output := formatDebugTermDecode(sixel)
+	if !strings.Contains(output, "DCS SIXEL params=1;2;3 pixels≈5x12") {
+		t.Fatalf("unexpected sixel decode: %q", output)
+	}
+}
+
+func TestFormatDebugTermDecodeKitty(t *testing.T) {
+	data := []byte("\x1b_Ga=T,f=100,s=10,v=20;" + "aGVsbG8=" + "\x1b\\")
+	output := formatDebugTermDecode(data)
+	if !strings.Contains(output, "APC KITTY a=T f=100 s=10x20 payload=<8 bytes base64>") {
+		t.Fatalf("unexpected kitty decode: %q", output)
+	}
+}
+
+func TestFormatDebugTermDecodeITerm2File(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString([]byte("hello world"))
+	data := []byte("\x1b]1337;File=name=dGVzdC5wbmc=;size=11;inline=1:" + payload + "\x07")
+	output := formatDebugTermDecode(data)
+	if !strings.Contains(output, "OSC 1337 iTerm2-File name=test.png size=11 bytes=11") {
+		t.Fatalf("unexpected iTerm2 file decode: %q", output)
+	}
+}
+
+func TestFormatDebugTermDecodeLargeUnknownPayloadElided(t *testing.T) {
+	payload := strings.Repeat("x", debugTermLargePayloadThreshold+1)
+	data := []byte("\x1bP" + payload + "\x1b\\")
+	output := formatDebugTermDecode(data)
+	if strings.Contains(output, payload) {
+		t.Fatalf("expected large DCS payload to be elided, got %q", output)
+	}
+	if !strings.Contains(output, "sha256:") {
+		t.Fatalf("expected elided payload to include a sha256 prefix, got %q", output)
+	}
+}
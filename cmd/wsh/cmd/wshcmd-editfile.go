@@ -0,0 +1,55 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wavetermdev/waveterm/pkg/util/fileutil"
+)
+
+var editFileCmd = &cobra.Command{
+	Use:                   "editfile <file>",
+	Short:                 "open a file in $EDITOR and print the resulting edits as EditSpec patches",
+	Args:                  cobra.ExactArgs(1),
+	RunE:                  editFileRun,
+	DisableFlagsInUseLine: true,
+	Hidden:                true,
+}
+
+var editFileEditor string
+
+func init() {
+	rootCmd.AddCommand(editFileCmd)
+	editFileCmd.Flags().StringVar(&editFileEditor, "editor", "", "editor to spawn (defaults to $EDITOR, then vi)")
+}
+
+// editFileRun opens filePath in the user's editor and prints the diff as a JSON array of
+// fileutil.EditSpec, the same representation an LLM tool call emits, so agent-generated
+// and human-generated edits flow through one downstream code path (fileutil.ApplyEdits).
+func editFileRun(cmd *cobra.Command, args []string) (rtnErr error) {
+	defer func() {
+		sendActivity("editfile", rtnErr == nil)
+	}()
+	filePath, err := fileutil.FixPath(args[0])
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+	specs, err := fileutil.EditInEditor(filePath, fileutil.EditorOptions{Editor: editFileEditor})
+	if err != nil {
+		return fmt.Errorf("editing %q: %w", filePath, err)
+	}
+	if len(specs) == 0 {
+		WriteStdout("no changes\n")
+		return nil
+	}
+	buf, err := json.MarshalIndent(specs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling edits: %w", err)
+	}
+	WriteStdout("%s\n", buf)
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wavetermdev/waveterm/pkg/wshrpc"
+	"github.com/wavetermdev/waveterm/pkg/wshrpc/wshclient"
+)
+
+type asciicastEvent struct {
+	Elapsed float64
+	Kind    string
+	Data    string
+}
+
+var termPlayCmd = &cobra.Command{
+	Use:                   "termplay <castfile>",
+	Short:                 "replay an asciicast v2 recording into a block's terminal",
+	Args:                  cobra.ExactArgs(1),
+	RunE:                  termPlayRun,
+	PreRunE:               preRunSetupRpcClient,
+	DisableFlagsInUseLine: true,
+	Hidden:                true,
+}
+
+var (
+	termPlaySpeed         float64
+	termPlayIdleTimeLimit float64
+)
+
+func init() {
+	rootCmd.AddCommand(termPlayCmd)
+	termPlayCmd.Flags().Float64Var(&termPlaySpeed, "speed", 1.0, "playback speed multiplier")
+	termPlayCmd.Flags().Float64Var(&termPlayIdleTimeLimit, "idle-time-limit", 0, "cap gaps between events to this many seconds (0 = no cap)")
+}
+
+func termPlayRun(cmd *cobra.Command, args []string) (rtnErr error) {
+	defer func() {
+		sendActivity("termplay", rtnErr == nil)
+	}()
+	if termPlaySpeed <= 0 {
+		return fmt.Errorf("speed must be greater than 0")
+	}
+	fullORef, err := resolveBlockArg()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("opening cast file: %w", err)
+	}
+	defer f.Close()
+	_, events, err := readAsciicastFile(f)
+	if err != nil {
+		return fmt.Errorf("parsing cast file: %w", err)
+	}
+	if termPlayIdleTimeLimit > 0 {
+		events = clampIdleGaps(events, termPlayIdleTimeLimit)
+	}
+
+	send := func(kind, data string) error {
+		if kind != asciicastEventOutput {
+			return nil
+		}
+		return wshclient.TermWriteDataCommand(RpcClient, wshrpc.CommandTermWriteData{
+			BlockId: fullORef.OID,
+			Data:    []byte(data),
+		}, &wshrpc.RpcOpts{Timeout: 2000})
+	}
+	fmt.Fprintf(os.Stderr, "replaying %s into block %s\n", args[0], fullORef)
+	return playTermSession(events, termPlaySpeed, time.Sleep, send)
+}
+
+// readAsciicastFile parses an asciicast v2 recording: a header object on the first line
+// followed by one `[elapsed, kind, data]` event array per line.
+func readAsciicastFile(r interface{ Read([]byte) (int, error) }) (AsciicastHeader, []asciicastEvent, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	var header AsciicastHeader
+	var events []asciicastEvent
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if lineNum == 1 {
+			if err := json.Unmarshal(line, &header); err != nil {
+				return header, nil, fmt.Errorf("parsing header: %w", err)
+			}
+			continue
+		}
+		var raw []json.RawMessage
+		if err := json.Unmarshal(line, &raw); err != nil {
+			return header, nil, fmt.Errorf("parsing event on line %d: %w", lineNum, err)
+		}
+		if len(raw) != 3 {
+			return header, nil, fmt.Errorf("event on line %d has %d elements, expected 3", lineNum, len(raw))
+		}
+		var ev asciicastEvent
+		if err := json.Unmarshal(raw[0], &ev.Elapsed); err != nil {
+			return header, nil, fmt.Errorf("parsing elapsed time on line %d: %w", lineNum, err)
+		}
+		if err := json.Unmarshal(raw[1], &ev.Kind); err != nil {
+			return header, nil, fmt.Errorf("parsing event kind on line %d: %w", lineNum, err)
+		}
+		if err := json.Unmarshal(raw[2], &ev.Data); err != nil {
+			return header, nil, fmt.Errorf("parsing event data on line %d: %w", lineNum, err)
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return header, nil, err
+	}
+	return header, events, nil
+}
+
+// clampIdleGaps rewrites the elapsed timestamps of events so that no gap between consecutive
+// events exceeds idleTimeLimit seconds, shifting every later event back by the excess.
+func clampIdleGaps(events []asciicastEvent, idleTimeLimit float64) []asciicastEvent {
+	if idleTimeLimit <= 0 || len(events) == 0 {
+		return events
+	}
+	out := make([]asciicastEvent, len(events))
+	var shift float64
+	prevElapsed := 0.0
+	for i, ev := range events {
+		gap := ev.Elapsed - prevElapsed
+		if gap > idleTimeLimit {
+			shift += gap - idleTimeLimit
+		}
+		prevElapsed = ev.Elapsed
+		out[i] = ev
+		out[i].Elapsed -= shift
+	}
+	return out
+}
+
+// playTermSession walks events in order, sleeping (scaled by speed) to reproduce the
+// original timing between them, and invokes send for each event's (kind, data).
+func playTermSession(events []asciicastEvent, speed float64, sleep func(time.Duration), send func(kind, data string) error) error {
+	prevElapsed := 0.0
+	for _, ev := range events {
+		gap := ev.Elapsed - prevElapsed
+		prevElapsed = ev.Elapsed
+		if gap > 0 {
+			sleep(time.Duration(gap / speed * float64(time.Second)))
+		}
+		if err := send(ev.Kind, ev.Data); err != nil {
+			return fmt.Errorf("replaying event: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadAsciicastFile(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24}
+[0.1,"o","hello "]
+[0.2,"o","world"]
+[0.5,"i","\r"]
+`
+	header, events, err := readAsciicastFile(strings.NewReader(cast))
+	if err != nil {
+		t.Fatalf("readAsciicastFile() error: %v", err)
+	}
+	if header.Width != 80 || header.Height != 24 {
+		t.Fatalf("unexpected header: %+v", header)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if events[1].Data != "world" || events[1].Kind != "o" {
+		t.Fatalf("unexpected event: %+v", events[1])
+	}
+	if events[2].Kind != "i" {
+		t.Fatalf("expected input event, got %+v", events[2])
+	}
+}
+
+func TestClampIdleGaps(t *testing.T) {
+	events := []asciicastEvent{
+		{Elapsed: 0.1, Kind: "o", Data: "a"},
+		{Elapsed: 10.1, Kind: "o", Data: "b"},
+		{Elapsed: 10.3, Kind: "o", Data: "c"},
+	}
+	clamped := clampIdleGaps(events, 1.0)
+	if clamped[0].Elapsed != 0.1 {
+		t.Fatalf("first event should be untouched, got %v", clamped[0].Elapsed)
+	}
+	if math.Abs(clamped[1].Elapsed-1.1) > 1e-9 {
+		t.Fatalf("expected gap clamped to 1.0, got %v", clamped[1].Elapsed)
+	}
+	if math.Abs(clamped[2].Elapsed-1.3) > 1e-9 {
+		t.Fatalf("expected later events shifted by the same amount, got %v", clamped[2].Elapsed)
+	}
+}
+
+func TestPlayTermSession(t *testing.T) {
+	events := []asciicastEvent{
+		{Elapsed: 0.1, Kind: "o", Data: "a"},
+		{Elapsed: 0.2, Kind: "i", Data: "x"},
+		{Elapsed: 0.4, Kind: "o", Data: "b"},
+	}
+	var slept []time.Duration
+	var sent []string
+	sleep := func(d time.Duration) { slept = append(slept, d) }
+	send := func(kind, data string) error {
+		sent = append(sent, kind+":"+data)
+		return nil
+	}
+	if err := playTermSession(events, 1.0, sleep, send); err != nil {
+		t.Fatalf("playTermSession() error: %v", err)
+	}
+	if len(sent) != 3 {
+		t.Fatalf("expected all 3 events sent, got %v", sent)
+	}
+	if len(slept) != 3 {
+		t.Fatalf("expected a sleep before each event, got %d", len(slept))
+	}
+	if slept[2] != 200*time.Millisecond {
+		t.Fatalf("expected 200ms gap before third event, got %v", slept[2])
+	}
+}
+
+func TestPlayTermSessionSpeed(t *testing.T) {
+	events := []asciicastEvent{{Elapsed: 1.0, Kind: "o", Data: "a"}}
+	var slept time.Duration
+	sleep := func(d time.Duration) { slept = d }
+	send := func(kind, data string) error { return nil }
+	if err := playTermSession(events, 2.0, sleep, send); err != nil {
+		t.Fatalf("playTermSession() error: %v", err)
+	}
+	if slept != 500*time.Millisecond {
+		t.Fatalf("expected 2x speed to halve the gap, got %v", slept)
+	}
+}
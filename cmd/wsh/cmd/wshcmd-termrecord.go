@@ -0,0 +1,192 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/wavetermdev/waveterm/pkg/wshrpc"
+	"github.com/wavetermdev/waveterm/pkg/wshrpc/wshclient"
+)
+
+// AsciicastHeader is the first line of an asciicast v2 recording.
+// See https://docs.asciinema.org/manual/asciicast/v2/
+type AsciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+const (
+	asciicastEventOutput = "o"
+	asciicastEventInput  = "i"
+)
+
+var termRecordCmd = &cobra.Command{
+	Use:                   "termrecord",
+	Short:                 "record a block's terminal output to an asciicast v2 file",
+	RunE:                  termRecordRun,
+	PreRunE:               preRunSetupRpcClient,
+	DisableFlagsInUseLine: true,
+	Hidden:                true,
+}
+
+var (
+	termRecordOutput   string
+	termRecordWidth    int
+	termRecordHeight   int
+	termRecordInterval time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(termRecordCmd)
+	termRecordCmd.Flags().StringVarP(&termRecordOutput, "output", "o", "", "asciicast output file (required)")
+	termRecordCmd.Flags().IntVar(&termRecordWidth, "width", 80, "terminal width to record in the cast header")
+	termRecordCmd.Flags().IntVar(&termRecordHeight, "height", 24, "terminal height to record in the cast header")
+	termRecordCmd.Flags().DurationVar(&termRecordInterval, "poll-interval", 100*time.Millisecond, "how often to poll the terminal for new output")
+	termRecordCmd.MarkFlagRequired("output")
+}
+
+func termRecordRun(cmd *cobra.Command, args []string) (rtnErr error) {
+	defer func() {
+		sendActivity("termrecord", rtnErr == nil)
+	}()
+	fullORef, err := resolveBlockArg()
+	if err != nil {
+		return err
+	}
+	outFile, err := os.Create(termRecordOutput)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	defer outFile.Close()
+	w := bufio.NewWriter(outFile)
+	defer w.Flush()
+
+	fetch := func() ([]byte, error) {
+		rtn, err := wshclient.DebugTermCommand(RpcClient, wshrpc.CommandDebugTermData{
+			BlockId: fullORef.OID,
+			Size:    64 * 1024,
+		}, &wshrpc.RpcOpts{Timeout: 2000})
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.DecodeString(rtn.Data64)
+	}
+
+	fmt.Fprintf(os.Stderr, "recording block %s to %s (ctrl-c to stop)\n", fullORef, termRecordOutput)
+	return recordTermSession(w, fetch, termRecordInterval, cmd.Context().Done())
+}
+
+// recordTermSession polls fetch for the current contents of the terminal's ring buffer and
+// writes out an asciicast v2 recording of the bytes that are new since the previous poll.
+// Each event is timestamped with the monotonic elapsed seconds since the first write, and
+// a partial UTF-8 rune at the end of a poll is held back until the next poll completes it,
+// so events never split a multi-byte character.
+func recordTermSession(w io.Writer, fetch func() ([]byte, error), pollInterval time.Duration, done <-chan struct{}) error {
+	if err := writeAsciicastHeader(w, termRecordWidth, termRecordHeight, nil); err != nil {
+		return fmt.Errorf("writing asciicast header: %w", err)
+	}
+	start := time.Now()
+	var last []byte
+	var pending []byte
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-ticker.C:
+			cur, err := fetch()
+			if err != nil {
+				return fmt.Errorf("fetching terminal data: %w", err)
+			}
+			fresh := diffTermBuffer(last, cur)
+			last = cur
+			if len(fresh) == 0 {
+				continue
+			}
+			pending = append(pending, fresh...)
+			chunk, rest := splitValidUTF8(pending)
+			pending = rest
+			if len(chunk) == 0 {
+				continue
+			}
+			elapsed := time.Since(start).Seconds()
+			if err := writeAsciicastEvent(w, elapsed, asciicastEventOutput, string(chunk)); err != nil {
+				return fmt.Errorf("writing asciicast event: %w", err)
+			}
+		}
+	}
+}
+
+// diffTermBuffer returns the bytes in cur that are new since last. The terminal side exposes
+// a ring buffer snapshot rather than an incremental stream, so if last is a prefix of cur we
+// only emit the suffix; otherwise the buffer has rotated past what we saw last time and we
+// treat the whole snapshot as new (better to duplicate a little context than drop output).
+func diffTermBuffer(last, cur []byte) []byte {
+	if len(last) == 0 {
+		return cur
+	}
+	if bytes.HasPrefix(cur, last) {
+		return cur[len(last):]
+	}
+	return cur
+}
+
+// splitValidUTF8 splits data at the last full rune boundary, returning (complete, remainder).
+// The remainder (an incomplete trailing multi-byte rune, if any) should be prepended to the
+// next chunk before it is split again.
+func splitValidUTF8(data []byte) (complete, remainder []byte) {
+	if len(data) == 0 || utf8.Valid(data) {
+		return data, nil
+	}
+	i := len(data)
+	for i > 0 && i > len(data)-utf8.UTFMax {
+		i--
+		if utf8.RuneStart(data[i]) {
+			if utf8.Valid(data[:i]) {
+				return data[:i], data[i:]
+			}
+			break
+		}
+	}
+	return data, nil
+}
+
+func writeAsciicastHeader(w io.Writer, width, height int, env map[string]string) error {
+	header := AsciicastHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: time.Now().Unix(),
+		Env:       env,
+	}
+	buf, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", buf)
+	return err
+}
+
+func writeAsciicastEvent(w io.Writer, elapsed float64, kind string, data string) error {
+	buf, err := json.Marshal([]interface{}{elapsed, kind, data})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", buf)
+	return err
+}
@@ -0,0 +1,85 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordTermSessionWritesAsciicast(t *testing.T) {
+	chunks := [][]byte{[]byte("hello "), []byte("hello world")}
+	i := 0
+	fetch := func() ([]byte, error) {
+		if i >= len(chunks) {
+			return chunks[len(chunks)-1], nil
+		}
+		c := chunks[i]
+		i++
+		return c, nil
+	}
+	done := make(chan struct{})
+	var buf bytes.Buffer
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		close(done)
+	}()
+	if err := recordTermSession(&buf, fetch, 5*time.Millisecond, done); err != nil {
+		t.Fatalf("recordTermSession() error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected a header line plus at least one event, got %d lines", len(lines))
+	}
+	if !strings.Contains(lines[0], `"version":2`) {
+		t.Fatalf("first line is not an asciicast header: %q", lines[0])
+	}
+	if !strings.Contains(buf.String(), `"world"`) {
+		t.Fatalf("expected recorded output to contain new bytes, got %q", buf.String())
+	}
+}
+
+func TestDiffTermBuffer(t *testing.T) {
+	if got := string(diffTermBuffer(nil, []byte("abc"))); got != "abc" {
+		t.Fatalf("expected full buffer on first read, got %q", got)
+	}
+	if got := string(diffTermBuffer([]byte("abc"), []byte("abcdef"))); got != "def" {
+		t.Fatalf("expected suffix diff, got %q", got)
+	}
+	if got := string(diffTermBuffer([]byte("abc"), []byte("xyz"))); got != "xyz" {
+		t.Fatalf("expected whole buffer on rotation, got %q", got)
+	}
+}
+
+func TestSplitValidUTF8(t *testing.T) {
+	// "é" is 2 bytes (0xC3 0xA9); truncate mid-rune.
+	data := append([]byte("hi"), 0xC3)
+	complete, remainder := splitValidUTF8(data)
+	if string(complete) != "hi" {
+		t.Fatalf("expected complete=%q, got %q", "hi", complete)
+	}
+	if len(remainder) != 1 {
+		t.Fatalf("expected 1 pending byte, got %d", len(remainder))
+	}
+	full := append(remainder, 0xA9)
+	complete2, remainder2 := splitValidUTF8(full)
+	if string(complete2) != "é" {
+		t.Fatalf("expected completed rune, got %q", complete2)
+	}
+	if len(remainder2) != 0 {
+		t.Fatalf("expected no remainder, got %d bytes", len(remainder2))
+	}
+}
+
+func TestWriteAsciicastEvent(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeAsciicastEvent(&buf, 1.5, asciicastEventOutput, "hi\n"); err != nil {
+		t.Fatalf("writeAsciicastEvent() error: %v", err)
+	}
+	if got := buf.String(); got != `[1.5,"o","hi\n"]`+"\n" {
+		t.Fatalf("unexpected event line: %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wavetermdev/waveterm/pkg/termevents"
+)
+
+var termVerifyCmd = &cobra.Command{
+	Use:                   "termverify <manifest-file>",
+	Short:                 "verify a recorded terminal session's signed manifest",
+	Args:                  cobra.ExactArgs(1),
+	RunE:                  termVerifyRun,
+	DisableFlagsInUseLine: true,
+	Hidden:                true,
+}
+
+var (
+	termVerifyPubKey     string
+	termVerifyByteStream string
+	termVerifyAsciicast  string
+)
+
+func init() {
+	rootCmd.AddCommand(termVerifyCmd)
+	termVerifyCmd.Flags().StringVar(&termVerifyPubKey, "pubkey", "", "hex-encoded ed25519 public key (required)")
+	termVerifyCmd.Flags().StringVar(&termVerifyByteStream, "bytestream", "", "path to the recorded raw byte stream, to verify against the manifest's hash")
+	termVerifyCmd.Flags().StringVar(&termVerifyAsciicast, "asciicast", "", "path to the recorded asciicast file, to verify against the manifest's hash")
+	termVerifyCmd.MarkFlagRequired("pubkey")
+}
+
+func termVerifyRun(cmd *cobra.Command, args []string) (rtnErr error) {
+	defer func() {
+		sendActivity("termverify", rtnErr == nil)
+	}()
+	manifestBytes, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+	var manifest termevents.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return fmt.Errorf("parsing manifest: %w", err)
+	}
+	pubKeyBytes, err := hex.DecodeString(termVerifyPubKey)
+	if err != nil || len(pubKeyBytes) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid --pubkey: expected %d hex-encoded bytes", ed25519.PublicKeySize)
+	}
+	pubKey := ed25519.PublicKey(pubKeyBytes)
+
+	if err := termevents.VerifyManifestSignature(pubKey, manifest); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+	WriteStdout("signature: ok (signed by %s)\n", manifest.PublicKeyFingerprint)
+
+	if termVerifyByteStream != "" {
+		if err := verifyDebugTermManifestHash("byte stream", termVerifyByteStream, manifest.ByteStreamSha256); err != nil {
+			return err
+		}
+	}
+	if termVerifyAsciicast != "" {
+		if err := verifyDebugTermManifestHash("asciicast", termVerifyAsciicast, manifest.AsciicastSha256); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func verifyDebugTermManifestHash(label, path, expectedSha256 string) error {
+	if expectedSha256 == "" {
+		return fmt.Errorf("manifest has no recorded hash for the %s", label)
+	}
+	actual, err := termevents.HashFile(path)
+	if err != nil {
+		return fmt.Errorf("hashing %s: %w", label, err)
+	}
+	if actual != expectedSha256 {
+		return fmt.Errorf("%s hash mismatch: manifest says %s, file hashes to %s", label, expectedSha256, actual)
+	}
+	WriteStdout("%s: ok (sha256:%s)\n", label, actual)
+	return nil
+}
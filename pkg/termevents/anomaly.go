@@ -0,0 +1,82 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Anomaly is a single window threshold breach detected in a block's output.
+type Anomaly struct {
+	Ts     int64
+	Kind   string
+	Detail string
+}
+
+// AnomalyDetector flags output bursts: more than ByteThreshold bytes published within
+// one WindowMs window. Windows are fixed, not sliding: a window opens at the first event
+// more than WindowMs after the current window's start (or at a breach), so a burst that
+// straddles two windows is not detected.
+type AnomalyDetector struct {
+	WindowMs      int64
+	ByteThreshold int
+
+	mu          sync.Mutex
+	windowStart int64
+	windowBytes int
+	onAnomaly   func(Anomaly)
+}
+
+func NewAnomalyDetector(windowMs int64, byteThreshold int, onAnomaly func(Anomaly)) *AnomalyDetector {
+	return &AnomalyDetector{WindowMs: windowMs, ByteThreshold: byteThreshold, onAnomaly: onAnomaly}
+}
+
+// Feed processes a single event, invoking onAnomaly at most once per breached window.
+func (d *AnomalyDetector) Feed(event Event) {
+	if event.Kind != EventKindOutput {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if event.Ts-d.windowStart > d.WindowMs {
+		d.windowStart = event.Ts
+		d.windowBytes = 0
+	}
+	d.windowBytes += len(event.Data)
+	if d.windowBytes <= d.ByteThreshold {
+		return
+	}
+	anomaly := Anomaly{
+		Ts:     event.Ts,
+		Kind:   "burst",
+		Detail: fmt.Sprintf("%d bytes within a %dms window (threshold %d)", d.windowBytes, d.WindowMs, d.ByteThreshold),
+	}
+	d.windowBytes = 0
+	d.windowStart = event.Ts
+	if d.onAnomaly != nil {
+		d.onAnomaly(anomaly)
+	}
+}
+
+// Consume subscribes to blockId's output events and feeds each one to the detector
+// until ctx is canceled, then unsubscribes.
+func (d *AnomalyDetector) Consume(ctx context.Context, blockId string) {
+	ch := Subscribe(blockId, Filter{Kinds: []string{EventKindOutput}})
+	go func() {
+		defer Unsubscribe(blockId, ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-ch:
+				if !ok {
+					return
+				}
+				d.Feed(ev)
+			}
+		}
+	}()
+}
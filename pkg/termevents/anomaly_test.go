@@ -0,0 +1,40 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import "testing"
+
+func TestAnomalyDetectorFlagsBurst(t *testing.T) {
+	var got []Anomaly
+	d := NewAnomalyDetector(1000, 10, func(a Anomaly) { got = append(got, a) })
+
+	d.Feed(Event{Kind: EventKindOutput, Ts: 0, Data: make([]byte, 5)})
+	if len(got) != 0 {
+		t.Fatalf("expected no anomaly yet, got %+v", got)
+	}
+	d.Feed(Event{Kind: EventKindOutput, Ts: 100, Data: make([]byte, 10)})
+	if len(got) != 1 {
+		t.Fatalf("expected exactly one anomaly once the threshold is crossed, got %+v", got)
+	}
+}
+
+func TestAnomalyDetectorResetsOutsideWindow(t *testing.T) {
+	var got []Anomaly
+	d := NewAnomalyDetector(1000, 10, func(a Anomaly) { got = append(got, a) })
+
+	d.Feed(Event{Kind: EventKindOutput, Ts: 0, Data: make([]byte, 8)})
+	d.Feed(Event{Kind: EventKindOutput, Ts: 5000, Data: make([]byte, 8)})
+	if len(got) != 0 {
+		t.Fatalf("expected the window to have reset, got %+v", got)
+	}
+}
+
+func TestAnomalyDetectorIgnoresNonOutputEvents(t *testing.T) {
+	var got []Anomaly
+	d := NewAnomalyDetector(1000, 1, func(a Anomaly) { got = append(got, a) })
+	d.Feed(Event{Kind: EventKindInput, Ts: 0, Data: make([]byte, 100)})
+	if len(got) != 0 {
+		t.Fatalf("expected input events not to trigger the output-byte detector, got %+v", got)
+	}
+}
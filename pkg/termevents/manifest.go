@@ -0,0 +1,89 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Manifest is emitted alongside a recorded terminal session (raw bytestream and/or
+// asciicast export) so it can later be proven unaltered: an ed25519 signature over the
+// session's identity and content hashes, plus the signing key's fingerprint.
+type Manifest struct {
+	BlockId              string `json:"blockId"`
+	StartTs              int64  `json:"startTs"`
+	EndTs                int64  `json:"endTs"`
+	ByteStreamSha256     string `json:"byteStreamSha256,omitempty"`
+	AsciicastSha256      string `json:"asciicastSha256,omitempty"`
+	PublicKeyFingerprint string `json:"publicKeyFingerprint"`
+	Signature            string `json:"signature"`
+}
+
+// manifestSigningBytes returns the canonical (signature-excluded) JSON encoding of m
+// that SignManifest/VerifyManifestSignature sign and verify over.
+func manifestSigningBytes(m Manifest) ([]byte, error) {
+	m.Signature = ""
+	return json.Marshal(m)
+}
+
+// PublicKeyFingerprint is a short, stable identifier for an ed25519 public key, suitable
+// for humans to eyeball-compare against a known signer.
+func PublicKeyFingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// SignManifest fills in m's PublicKeyFingerprint and Signature fields by signing m
+// (with Signature cleared) with priv.
+func SignManifest(priv ed25519.PrivateKey, m Manifest) (Manifest, error) {
+	m.PublicKeyFingerprint = PublicKeyFingerprint(priv.Public().(ed25519.PublicKey))
+	payload, err := manifestSigningBytes(m)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("encoding manifest: %w", err)
+	}
+	m.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, payload))
+	return m, nil
+}
+
+// VerifyManifestSignature checks that m.Signature is a valid ed25519 signature by pub
+// over m's other fields. It does not recompute the content hashes; callers should
+// compare m.ByteStreamSha256/m.AsciicastSha256 against HashFile of the actual artifacts.
+func VerifyManifestSignature(pub ed25519.PublicKey, m Manifest) error {
+	sig, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	payload, err := manifestSigningBytes(m)
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	if !ed25519.Verify(pub, payload, sig) {
+		return fmt.Errorf("signature does not match manifest contents")
+	}
+	if fp := PublicKeyFingerprint(pub); fp != m.PublicKeyFingerprint {
+		return fmt.Errorf("public key fingerprint %s does not match manifest's %s", fp, m.PublicKeyFingerprint)
+	}
+	return nil
+}
+
+// HashFile returns the lowercase hex-encoded sha256 of the file at path.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import (
+	"crypto/ed25519"
+	"testing"
+)
+
+func TestSignAndVerifyManifest(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error: %v", err)
+	}
+	m := Manifest{
+		BlockId:          "block-1",
+		StartTs:          100,
+		EndTs:            200,
+		ByteStreamSha256: "deadbeef",
+	}
+	signed, err := SignManifest(priv, m)
+	if err != nil {
+		t.Fatalf("SignManifest() error: %v", err)
+	}
+	if signed.Signature == "" {
+		t.Fatal("expected a non-empty signature")
+	}
+	if signed.PublicKeyFingerprint != PublicKeyFingerprint(pub) {
+		t.Fatalf("fingerprint mismatch: %s vs %s", signed.PublicKeyFingerprint, PublicKeyFingerprint(pub))
+	}
+	if err := VerifyManifestSignature(pub, signed); err != nil {
+		t.Fatalf("VerifyManifestSignature() error: %v", err)
+	}
+}
+
+func TestVerifyManifestSignatureDetectsTampering(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	signed, err := SignManifest(priv, Manifest{BlockId: "block-1", ByteStreamSha256: "aaaa"})
+	if err != nil {
+		t.Fatalf("SignManifest() error: %v", err)
+	}
+	signed.ByteStreamSha256 = "bbbb"
+	if err := VerifyManifestSignature(pub, signed); err == nil {
+		t.Fatal("expected verification to fail after tampering with a hash field")
+	}
+}
+
+func TestVerifyManifestSignatureWrongKey(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(nil)
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	signed, err := SignManifest(priv, Manifest{BlockId: "block-1"})
+	if err != nil {
+		t.Fatalf("SignManifest() error: %v", err)
+	}
+	if err := VerifyManifestSignature(otherPub, signed); err == nil {
+		t.Fatal("expected verification to fail against the wrong public key")
+	}
+}
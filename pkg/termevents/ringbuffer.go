@@ -0,0 +1,61 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import (
+	"context"
+	"sync"
+)
+
+// RingBuffer is the fixed-capacity subscriber the debugterm RPC path reads from: it
+// keeps only the most recent Capacity bytes of a block's output.
+type RingBuffer struct {
+	mu       sync.Mutex
+	buf      []byte
+	capacity int
+}
+
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{capacity: capacity}
+}
+
+func (r *RingBuffer) Append(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf = append(r.buf, data...)
+	if len(r.buf) > r.capacity {
+		r.buf = r.buf[len(r.buf)-r.capacity:]
+	}
+}
+
+// Snapshot returns a copy of the last size bytes (or everything, if size <= 0 or larger
+// than what's buffered).
+func (r *RingBuffer) Snapshot(size int64) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if size <= 0 || size >= int64(len(r.buf)) {
+		return append([]byte(nil), r.buf...)
+	}
+	return append([]byte(nil), r.buf[int64(len(r.buf))-size:]...)
+}
+
+// Consume subscribes to blockId's output events and appends each chunk to the ring
+// buffer until ctx is canceled, then unsubscribes.
+func (r *RingBuffer) Consume(ctx context.Context, blockId string) {
+	ch := Subscribe(blockId, Filter{Kinds: []string{EventKindOutput}})
+	go func() {
+		defer Unsubscribe(blockId, ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-ch:
+				if !ok {
+					return
+				}
+				r.Append(ev.Data)
+			}
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import "testing"
+
+func TestRingBufferAppendAndTruncate(t *testing.T) {
+	rb := NewRingBuffer(5)
+	rb.Append([]byte("abc"))
+	rb.Append([]byte("defgh"))
+	if got := string(rb.Snapshot(0)); got != "defgh" {
+		t.Fatalf("expected ring buffer to keep only the last 5 bytes, got %q", got)
+	}
+}
+
+func TestRingBufferSnapshotSize(t *testing.T) {
+	rb := NewRingBuffer(100)
+	rb.Append([]byte("hello world"))
+	if got := string(rb.Snapshot(5)); got != "world" {
+		t.Fatalf("expected last 5 bytes, got %q", got)
+	}
+	if got := string(rb.Snapshot(1000)); got != "hello world" {
+		t.Fatalf("expected full buffer when size exceeds length, got %q", got)
+	}
+}
+
+func TestRingBufferConsume(t *testing.T) {
+	blockId := "ringbuf-block"
+	rb := NewRingBuffer(100)
+	ctx, cancel := testContext()
+	defer cancel()
+	rb.Consume(ctx, blockId)
+
+	Publish(Event{BlockId: blockId, Kind: EventKindOutput, Data: []byte("hi")})
+	Publish(Event{BlockId: blockId, Kind: EventKindInput, Data: []byte("ignored")})
+	waitForCondition(t, func() bool { return string(rb.Snapshot(0)) == "hi" })
+}
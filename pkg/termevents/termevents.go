@@ -0,0 +1,104 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package termevents is a pluggable event bus for a block's terminal PTY stream. The
+// PTY writer publishes one Event per chunk of bytes; subscribers (the ring buffer
+// debugterm reads, the asciicast recorder, a rolling anomaly detector, and future
+// consumers like search or LLM summarization) all read the same stream without the
+// write path knowing or caring who's listening.
+package termevents
+
+import (
+	"sync"
+)
+
+const (
+	EventKindOutput = "output"
+	EventKindInput  = "input"
+	EventKindStart  = "start"
+	EventKindEnd    = "end"
+)
+
+// Event is one published chunk of a block's terminal stream.
+type Event struct {
+	BlockId string
+	Ts      int64 // unix milliseconds
+	Kind    string
+	Data    []byte
+}
+
+// Filter selects which events a subscriber receives. A zero Filter matches everything.
+type Filter struct {
+	Kinds []string
+}
+
+func (f Filter) matches(e Event) bool {
+	if len(f.Kinds) == 0 {
+		return true
+	}
+	for _, k := range f.Kinds {
+		if k == e.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriberBufferSize bounds how many events a slow subscriber can fall behind by
+// before Publish starts dropping events for it rather than blocking the PTY write path.
+const subscriberBufferSize = 256
+
+type subscription struct {
+	ch     chan Event
+	filter Filter
+}
+
+type bus struct {
+	mu   sync.Mutex
+	subs map[string][]*subscription
+}
+
+var defaultBus = &bus{subs: make(map[string][]*subscription)}
+
+// Subscribe returns a channel of blockId's events matching filter. Callers must call
+// Unsubscribe with the same blockId and channel when done to release it.
+func Subscribe(blockId string, filter Filter) <-chan Event {
+	defaultBus.mu.Lock()
+	defer defaultBus.mu.Unlock()
+	sub := &subscription{ch: make(chan Event, subscriberBufferSize), filter: filter}
+	defaultBus.subs[blockId] = append(defaultBus.subs[blockId], sub)
+	return sub.ch
+}
+
+// Unsubscribe removes and closes a channel previously returned by Subscribe. It is a
+// no-op if the channel is not currently subscribed for blockId.
+func Unsubscribe(blockId string, ch <-chan Event) {
+	defaultBus.mu.Lock()
+	defer defaultBus.mu.Unlock()
+	subs := defaultBus.subs[blockId]
+	for i, sub := range subs {
+		if sub.ch == ch {
+			close(sub.ch)
+			defaultBus.subs[blockId] = append(subs[:i:i], subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Publish fans event out to every subscriber registered for event.BlockId whose filter
+// matches. A subscriber whose buffer is full has this event dropped rather than
+// blocking publication, since Publish sits on the PTY write path.
+func Publish(event Event) {
+	defaultBus.mu.Lock()
+	subs := append([]*subscription(nil), defaultBus.subs[event.BlockId]...)
+	defaultBus.mu.Unlock()
+	for _, sub := range subs {
+		if !sub.filter.matches(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
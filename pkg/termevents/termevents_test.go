@@ -0,0 +1,104 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribePublishUnsubscribe(t *testing.T) {
+	blockId := "block-1"
+	ch := Subscribe(blockId, Filter{})
+	defer Unsubscribe(blockId, ch)
+
+	Publish(Event{BlockId: blockId, Kind: EventKindOutput, Data: []byte("hi")})
+
+	select {
+	case ev := <-ch:
+		if string(ev.Data) != "hi" {
+			t.Fatalf("unexpected event data: %q", ev.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for published event")
+	}
+}
+
+func TestPublishFiltersByKind(t *testing.T) {
+	blockId := "block-2"
+	ch := Subscribe(blockId, Filter{Kinds: []string{EventKindInput}})
+	defer Unsubscribe(blockId, ch)
+
+	Publish(Event{BlockId: blockId, Kind: EventKindOutput, Data: []byte("out")})
+	Publish(Event{BlockId: blockId, Kind: EventKindInput, Data: []byte("in")})
+
+	select {
+	case ev := <-ch:
+		if ev.Kind != EventKindInput {
+			t.Fatalf("expected only input events, got %q", ev.Kind)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for published event")
+	}
+	select {
+	case ev := <-ch:
+		t.Fatalf("expected no further events, got %+v", ev)
+	default:
+	}
+}
+
+func TestPublishScopedToBlockId(t *testing.T) {
+	chA := Subscribe("block-a", Filter{})
+	defer Unsubscribe("block-a", chA)
+	chB := Subscribe("block-b", Filter{})
+	defer Unsubscribe("block-b", chB)
+
+	Publish(Event{BlockId: "block-a", Kind: EventKindOutput, Data: []byte("a")})
+
+	select {
+	case ev := <-chA:
+		if string(ev.Data) != "a" {
+			t.Fatalf("unexpected data for block-a: %q", ev.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for block-a's event")
+	}
+	select {
+	case ev := <-chB:
+		t.Fatalf("block-b should not have received block-a's event, got %+v", ev)
+	default:
+	}
+}
+
+func TestUnsubscribeClosesChannel(t *testing.T) {
+	blockId := "block-3"
+	ch := Subscribe(blockId, Filter{})
+	Unsubscribe(blockId, ch)
+	_, ok := <-ch
+	if ok {
+		t.Fatal("expected channel to be closed after Unsubscribe")
+	}
+}
+
+func TestPublishDropsWhenSubscriberBufferFull(t *testing.T) {
+	blockId := "block-4"
+	ch := Subscribe(blockId, Filter{})
+	defer Unsubscribe(blockId, ch)
+
+	for i := 0; i < subscriberBufferSize+10; i++ {
+		Publish(Event{BlockId: blockId, Kind: EventKindOutput, Data: []byte("x")})
+	}
+	count := 0
+	for {
+		select {
+		case <-ch:
+			count++
+		default:
+			if count != subscriberBufferSize {
+				t.Fatalf("expected exactly %d buffered events, got %d", subscriberBufferSize, count)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import (
+	"context"
+	"encoding/base64"
+)
+
+// DebugTermFollowChunk is one chunk HandleDebugTermFollow sends back to the wshclient
+// streaming call, base64-encoded the same way CommandDebugTermRtnData.Data64 is.
+type DebugTermFollowChunk struct {
+	Data64 string
+}
+
+// HandleDebugTermFollow is the server-side implementation of
+// wshrpc.CommandDebugTermFollowData: it subscribes blockId's live output events and
+// streams each chunk back until ctx is canceled. It first sends the whole of history's
+// RingBuffer.Snapshot (if non-empty), then every live output event. It applies no time
+// bounds: neither the history nor the live events are filtered by timestamp.
+func HandleDebugTermFollow(ctx context.Context, blockId string, history *RingBuffer) <-chan DebugTermFollowChunk {
+	out := make(chan DebugTermFollowChunk, subscriberBufferSize)
+	ch := Subscribe(blockId, Filter{Kinds: []string{EventKindOutput}})
+	go func() {
+		defer close(out)
+		defer Unsubscribe(blockId, ch)
+		if history != nil {
+			if snap := history.Snapshot(0); len(snap) > 0 {
+				out <- DebugTermFollowChunk{Data64: base64.StdEncoding.EncodeToString(snap)}
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-ch:
+				if !ok {
+					return
+				}
+				out <- DebugTermFollowChunk{Data64: base64.StdEncoding.EncodeToString(ev.Data)}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,32 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestHandleDebugTermFollowStreamsHistoryThenLiveEvents(t *testing.T) {
+	blockId := "wshhandlers-follow-block"
+	history := NewRingBuffer(100)
+	history.Append([]byte("past output"))
+
+	ctx, cancel := testContext()
+	defer cancel()
+	out := HandleDebugTermFollow(ctx, blockId, history)
+
+	first := <-out
+	firstData, err := base64.StdEncoding.DecodeString(first.Data64)
+	if err != nil || string(firstData) != "past output" {
+		t.Fatalf("expected the first chunk to replay history, got %q, err: %v", firstData, err)
+	}
+
+	Publish(Event{BlockId: blockId, Kind: EventKindOutput, Data: []byte("live output")})
+	second := <-out
+	secondData, err := base64.StdEncoding.DecodeString(second.Data64)
+	if err != nil || string(secondData) != "live output" {
+		t.Fatalf("expected the second chunk to be the live event, got %q, err: %v", secondData, err)
+	}
+}
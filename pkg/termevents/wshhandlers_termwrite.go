@@ -0,0 +1,46 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	ptyWritersMu sync.Mutex
+	ptyWriters   = make(map[string]io.Writer)
+)
+
+// RegisterPtyWriter records blockId's live PTY writer so HandleTermWriteData can deliver
+// replayed bytes to it. The block controller that owns the PTY calls this when the block
+// starts and UnregisterPtyWriter when it stops.
+func RegisterPtyWriter(blockId string, w io.Writer) {
+	ptyWritersMu.Lock()
+	defer ptyWritersMu.Unlock()
+	ptyWriters[blockId] = w
+}
+
+// UnregisterPtyWriter removes blockId's PTY writer, once it's no longer live.
+func UnregisterPtyWriter(blockId string) {
+	ptyWritersMu.Lock()
+	defer ptyWritersMu.Unlock()
+	delete(ptyWriters, blockId)
+}
+
+// HandleTermWriteData is the server-side implementation of wshrpc.CommandTermWriteData:
+// it writes data straight into blockId's registered PTY, exactly as if it had arrived
+// from the terminal process, so termplay's replayed bytes flow through the same
+// Publish path (ring buffer, recorders, anomaly detector) a live session's output does.
+func HandleTermWriteData(blockId string, data []byte) error {
+	ptyWritersMu.Lock()
+	w, ok := ptyWriters[blockId]
+	ptyWritersMu.Unlock()
+	if !ok {
+		return fmt.Errorf("no live pty writer registered for block %q", blockId)
+	}
+	_, err := w.Write(data)
+	return err
+}
@@ -0,0 +1,29 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package termevents
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHandleTermWriteDataWritesToRegisteredPty(t *testing.T) {
+	blockId := "wshhandlers-write-block"
+	var buf bytes.Buffer
+	RegisterPtyWriter(blockId, &buf)
+	defer UnregisterPtyWriter(blockId)
+
+	if err := HandleTermWriteData(blockId, []byte("hello")); err != nil {
+		t.Fatalf("HandleTermWriteData failed: %v", err)
+	}
+	if buf.String() != "hello" {
+		t.Fatalf("expected the pty writer to receive the data, got %q", buf.String())
+	}
+}
+
+func TestHandleTermWriteDataErrorsWithoutRegisteredPty(t *testing.T) {
+	if err := HandleTermWriteData("no-such-block", []byte("x")); err == nil {
+		t.Fatal("expected an error when no pty writer is registered for the block")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// EditorOptions configures EditInEditor.
+type EditorOptions struct {
+	// Editor overrides $EDITOR (and, failing that, vi) as the program spawned on the
+	// snapshot. Wave's configured editor setting should be passed in here by callers
+	// that have access to it; fileutil itself doesn't read settings.
+	Editor string
+	// ContextLines is how many surrounding lines of unchanged context EditInEditor
+	// includes around each changed hunk before growing the window further to make
+	// OldStr unique. Defaults to 2 (mirrors the default unified diff context).
+	ContextLines int
+}
+
+// EditInEditor snapshots filePath, opens the editor on the snapshot, waits for it to
+// exit, then diffs the edited snapshot against the original and returns the EditSpec
+// patches that reproduce the change through ApplyEdits. This gives human edits (via
+// this function) and LLM/agent-authored edits (constructed directly) the same
+// downstream representation, atomicity guarantees, and audit trail.
+//
+// It returns a nil slice, not an error, if the file comes back unchanged.
+func EditInEditor(filePath string, opts EditorOptions) ([]EditSpec, error) {
+	return EditInEditorFS(&BasicFS{}, filePath, opts)
+}
+
+// EditInEditorFS is EditInEditor against an arbitrary FS. The editor itself always runs
+// locally (there's no way to exec a process against a remote FS), so the file is read
+// through fsys, edited in a local temp file, and diffed; only the resulting EditSpecs
+// need to be applied back through fsys (e.g. with ReplaceInFileFS).
+func EditInEditorFS(fsys FS, filePath string, opts EditorOptions) ([]EditSpec, error) {
+	original, err := fsys.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", filePath, err)
+	}
+
+	tmpFile, err := os.CreateTemp("", "wsh-edit-*"+filepath.Ext(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("creating editor temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+	if _, err := tmpFile.Write(original); err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("writing editor temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return nil, fmt.Errorf("closing editor temp file: %w", err)
+	}
+
+	editorArgv := splitEditorCommand(resolveEditor(opts.Editor))
+	editCmd := exec.Command(editorArgv[0], append(editorArgv[1:], tmpPath)...)
+	editCmd.Stdin, editCmd.Stdout, editCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := editCmd.Run(); err != nil {
+		return nil, fmt.Errorf("running editor %q: %w", editorArgv[0], err)
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading editor output: %w", err)
+	}
+	if bytes.Equal(original, edited) {
+		return nil, nil
+	}
+
+	contextLines := opts.ContextLines
+	if contextLines <= 0 {
+		contextLines = 2
+	}
+	return diffToEditSpecs(original, edited, contextLines), nil
+}
+
+// resolveEditor picks the editor to spawn: editor if set, else $EDITOR, else vi.
+func resolveEditor(editor string) string {
+	if editor != "" {
+		return editor
+	}
+	if e := os.Getenv("EDITOR"); e != "" {
+		return e
+	}
+	return "vi"
+}
+
+// splitEditorCommand splits an editor command string like "code --wait" into argv, the
+// way a shell would split it on whitespace, since $EDITOR and Wave's configured editor
+// setting commonly carry flags (e.g. "code --wait", "emacsclient -t", "subl -w") rather
+// than naming a bare executable. Always returns at least one element.
+func splitEditorCommand(editor string) []string {
+	if fields := strings.Fields(editor); len(fields) > 0 {
+		return fields
+	}
+	return []string{editor}
+}
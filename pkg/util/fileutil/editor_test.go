@@ -0,0 +1,94 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeEditorScript writes a tiny script that appends text to whatever file it's invoked
+// on, standing in for a real $EDITOR in tests. It appends to its last argument rather
+// than $1, so it works whether it's invoked bare (scriptPath file) or with leading flags
+// (scriptPath --wait file), the way a real editor command line would be.
+func fakeEditorScript(t *testing.T, appendText string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor script is a shell script; skip on windows")
+	}
+	dir := t.TempDir()
+	scriptPath := filepath.Join(dir, "fake-editor.sh")
+	script := "#!/bin/sh\nfor f in \"$@\"; do target=\"$f\"; done\nprintf " + shellQuote(appendText) + " >> \"$target\"\n"
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		t.Fatalf("writing fake editor script: %v", err)
+	}
+	return scriptPath
+}
+
+func shellQuote(s string) string {
+	return "'" + s + "'"
+}
+
+func TestEditInEditorFSAppliesEditorChange(t *testing.T) {
+	editor := fakeEditorScript(t, "appended\n")
+	m := NewMemFS()
+	m.PutFile("/note.txt", []byte("original\n"), 0644)
+
+	specs, err := EditInEditorFS(m, "/note.txt", EditorOptions{Editor: editor})
+	if err != nil {
+		t.Fatalf("EditInEditorFS failed: %v", err)
+	}
+	if len(specs) == 0 {
+		t.Fatal("expected at least one EditSpec for a changed file")
+	}
+
+	applied, err := ApplyEdits([]byte("original\n"), specs)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(applied) != "original\nappended\n" {
+		t.Fatalf("unexpected applied content: %q", applied)
+	}
+}
+
+func TestEditInEditorFSSplitsEditorCommandWithFlags(t *testing.T) {
+	// A configured editor commonly carries flags ("code --wait", "subl -w"); the whole
+	// string must split into argv0 + args rather than being passed as one executable
+	// name.
+	editor := fakeEditorScript(t, "appended\n")
+	m := NewMemFS()
+	m.PutFile("/note.txt", []byte("original\n"), 0644)
+
+	specs, err := EditInEditorFS(m, "/note.txt", EditorOptions{Editor: editor + " --wait"})
+	if err != nil {
+		t.Fatalf("EditInEditorFS failed: %v", err)
+	}
+	if len(specs) == 0 {
+		t.Fatal("expected at least one EditSpec for a changed file")
+	}
+
+	applied, err := ApplyEdits([]byte("original\n"), specs)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(applied) != "original\nappended\n" {
+		t.Fatalf("unexpected applied content: %q", applied)
+	}
+}
+
+func TestEditInEditorFSNoChangeReturnsNilSpecs(t *testing.T) {
+	editor := fakeEditorScript(t, "")
+	m := NewMemFS()
+	m.PutFile("/note.txt", []byte("unchanged\n"), 0644)
+
+	specs, err := EditInEditorFS(m, "/note.txt", EditorOptions{Editor: editor})
+	if err != nil {
+		t.Fatalf("EditInEditorFS failed: %v", err)
+	}
+	if specs != nil {
+		t.Fatalf("expected nil specs when the editor makes no change, got %+v", specs)
+	}
+}
@@ -4,7 +4,8 @@
 package fileutil
 
 import (
-	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/fs"
@@ -18,6 +19,15 @@ import (
 	"github.com/wavetermdev/waveterm/pkg/wavebase"
 )
 
+// randSuffix returns a short random hex string used to give FS-generic temp files
+// (AtomicWriteFileWithOptionsFS) unique names without relying on os.CreateTemp, which
+// only local disks support.
+func randSuffix() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
 func FixPath(path string) (string, error) {
 	origPath := path
 	var err error
@@ -66,57 +76,126 @@ func WinSymlinkDir(path string, bits os.FileMode) bool {
 // can pass an existing fileInfo to avoid re-statting the file
 // falls back to text/plain for 0 byte files
 func DetectMimeType(path string, fileInfo fs.FileInfo, extended bool) string {
+	return DetectMimeTypeFS(&BasicFS{}, path, fileInfo, extended)
+}
+
+// DetectMimeTypeOptions configures the content-sniffing fallback DetectMimeTypeFS falls
+// back to when the extension map doesn't recognize the file.
+type DetectMimeTypeOptions struct {
+	// SniffSize is how many bytes of file content to read for http.DetectContentType and
+	// the magic-number/text-format sniffer chain. Zero uses DefaultSniffSize.
+	SniffSize int
+}
+
+// DetectMimeTypeFS is DetectMimeType against an arbitrary FS, so the same detection
+// works for a remote (SFTP) pane or an in-memory test tree, not just the local disk.
+func DetectMimeTypeFS(fsys FS, path string, fileInfo fs.FileInfo, extended bool) string {
+	return DetectMimeTypeFSWithOptions(fsys, path, fileInfo, extended, DetectMimeTypeOptions{})
+}
+
+// DetectMimeTypeFSWithOptions is DetectMimeTypeFS with control over how much of the file
+// the content-sniffing fallback reads.
+func DetectMimeTypeFSWithOptions(fsys FS, path string, fileInfo fs.FileInfo, extended bool, opts DetectMimeTypeOptions) string {
+	mimeType, _ := detectMimeTypeFS(fsys, path, fileInfo, extended, true, opts)
+	return mimeType
+}
+
+// detectMimeTypeFS is the shared implementation behind DetectMimeTypeFS and MimePolicy.
+// suppressOctetStream controls whether a content-sniffed fallback to
+// "application/octet-stream" is reported as-is or discarded as "" (a detection
+// failure): the public API discards it, but MimePolicy needs to see it so a policy can
+// name "application/octet-stream" explicitly.
+func detectMimeTypeFS(fsys FS, path string, fileInfo fs.FileInfo, extended, suppressOctetStream bool, opts DetectMimeTypeOptions) (string, error) {
 	if fileInfo == nil {
-		statRtn, err := os.Stat(path)
+		statRtn, err := fsys.Stat(path)
 		if err != nil {
-			return ""
+			return "", err
 		}
 		fileInfo = statRtn
 	}
 
 	if fileInfo.IsDir() || WinSymlinkDir(path, fileInfo.Mode()) {
-		return "directory"
+		return "directory", nil
 	}
 	if fileInfo.Mode()&os.ModeNamedPipe == os.ModeNamedPipe {
-		return "pipe"
+		return "pipe", nil
 	}
 	charDevice := os.ModeDevice | os.ModeCharDevice
 	if fileInfo.Mode()&charDevice == charDevice {
-		return "character-special"
+		return "character-special", nil
 	}
 	if fileInfo.Mode()&os.ModeDevice == os.ModeDevice {
-		return "block-special"
+		return "block-special", nil
 	}
 	ext := strings.ToLower(filepath.Ext(path))
 	if mimeType, ok := StaticMimeTypeMap[ext]; ok {
-		return mimeType
+		return mimeType, nil
 	}
 	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
-		return mimeType
+		return mimeType, nil
 	}
 	if fileInfo.Size() == 0 {
-		return "text/plain"
+		return "text/plain", nil
 	}
 	if !extended {
-		return ""
+		return "", nil
+	}
+	sniffSize := opts.SniffSize
+	if sniffSize <= 0 {
+		sniffSize = DefaultSniffSize
+	}
+	cacheKey := mimeCacheKey{uri: fsys.URI(), path: path, mtime: fileInfo.ModTime().UnixNano(), size: fileInfo.Size(), sniffSize: sniffSize}
+	rtn, ok := mimeCacheLookup(cacheKey)
+	if !ok {
+		var err error
+		rtn, err = sniffContent(fsys, path, fileInfo, sniffSize)
+		if err != nil {
+			return "", nil
+		}
+		mimeCacheStore(cacheKey, rtn)
+	}
+	if rtn == "application/octet-stream" && suppressOctetStream {
+		return "", nil
 	}
-	fd, err := os.Open(path)
+	return rtn, nil
+}
+
+// sniffContent reads up to sniffSize bytes of path's content and runs it through
+// http.DetectContentType, then the magic-number table and text-format heuristics in
+// sniffer.go: the magic table and the BOM/printable-ratio check only run when
+// http.DetectContentType couldn't classify the content at all (the "application/
+// octet-stream" fallback), but the text-format heuristics also run when it classified
+// the content as generic text, so they can upgrade "text/plain" to something more
+// specific like "application/json".
+func sniffContent(fsys FS, path string, fileInfo fs.FileInfo, sniffSize int) (string, error) {
+	fd, err := fsys.Open(path)
 	if err != nil {
-		return ""
+		return "", nil
 	}
 	defer fd.Close()
-	buf := make([]byte, 512)
+	buf := make([]byte, sniffSize)
 	// ignore the error (EOF / UnexpectedEOF is fine, just process how much we got back)
-	n, _ := io.ReadAtLeast(fd, buf, 512)
+	n, _ := io.ReadAtLeast(fd, buf, sniffSize)
 	if n == 0 {
-		return ""
+		return "", nil
 	}
 	buf = buf[:n]
 	rtn := http.DetectContentType(buf)
-	if rtn == "application/octet-stream" {
-		return ""
+	isOctetStream := rtn == "application/octet-stream"
+	if isOctetStream {
+		if mime := sniffMagic(buf, fd, fileInfo); mime != "" {
+			return mime, nil
+		}
 	}
-	return rtn
+	if isOctetStream || strings.HasPrefix(rtn, "text/plain") {
+		if mime := sniffText(buf); mime != "" {
+			return mime, nil
+		}
+	}
+	if isOctetStream && looksLikeText(buf) {
+		return "text/plain", nil
+	}
+	return rtn, nil
 }
 
 func DetectMimeTypeWithDirEnt(path string, dirEnt fs.DirEntry) string {
@@ -143,19 +222,160 @@ func DetectMimeTypeWithDirEnt(path string, dirEnt fs.DirEntry) string {
 	return ""
 }
 
+// AtomicWriteFileOptions controls the durability tradeoffs AtomicWriteFileWithOptions makes.
+type AtomicWriteFileOptions struct {
+	// Sync fsyncs the temp file before the rename and the parent directory after it
+	// (the latter is a no-op on Windows, where MoveFileEx is used instead).
+	Sync bool
+	// Mode is the file mode used to create the temp file. Defaults to 0644 if zero.
+	Mode os.FileMode
+	// Backup preserves the previous contents of fileName (if any) as fileName+".bak"
+	// before the rename, so a crash mid-write can still be recovered from by hand.
+	Backup bool
+}
+
+// AtomicWriteFile writes data to fileName by writing to a temp file and renaming it into
+// place, fsync-ing the temp file and its parent directory so the write survives a crash.
 func AtomicWriteFile(fileName string, data []byte, perm os.FileMode) error {
-	tmpFileName := fileName + TempFileSuffix
-	if err := os.WriteFile(tmpFileName, data, perm); err != nil {
+	return AtomicWriteFileWithOptions(fileName, data, AtomicWriteFileOptions{Sync: true, Mode: perm})
+}
+
+// AtomicWriteFileWithOptions is AtomicWriteFile with explicit durability/backup options.
+// On Unix it writes the temp file, fsyncs it, renames it over fileName, then fsyncs the
+// parent directory so the rename itself survives a crash. On Windows, where os.Rename
+// isn't always atomic when the destination exists, it instead uses MoveFileEx with
+// MOVEFILE_REPLACE_EXISTING|MOVEFILE_WRITE_THROUGH.
+func AtomicWriteFileWithOptions(fileName string, data []byte, opts AtomicWriteFileOptions) error {
+	mode := opts.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	// Each writer gets its own uniquely-named temp file (rather than a fixed
+	// fileName+TempFileSuffix) so concurrent writers to the same fileName don't
+	// stomp on each other's in-progress temp file before either one renames.
+	f, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".*"+TempFileSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for %q: %w", fileName, err)
+	}
+	tmpFileName := f.Name()
+	if err := os.Chmod(tmpFileName, mode); err != nil {
+		f.Close()
+		os.Remove(tmpFileName)
+		return fmt.Errorf("failed to set mode on temp file %q: %w", tmpFileName, err)
+	}
+	cleanupTemp := func(writeErr error) error {
+		f.Close()
 		if removeErr := os.Remove(tmpFileName); removeErr != nil && !os.IsNotExist(removeErr) {
-			return fmt.Errorf("failed to write temp file %q: %w (also failed to remove temp file: %v)", tmpFileName, err, removeErr)
+			return fmt.Errorf("%w (also failed to remove temp file: %v)", writeErr, removeErr)
 		}
-		return err
+		return writeErr
+	}
+	if _, err := f.Write(data); err != nil {
+		return cleanupTemp(fmt.Errorf("failed to write temp file %q: %w", tmpFileName, err))
+	}
+	if opts.Sync {
+		if err := f.Sync(); err != nil {
+			return cleanupTemp(fmt.Errorf("failed to fsync temp file %q: %w", tmpFileName, err))
+		}
+	}
+	if err := f.Close(); err != nil {
+		return cleanupTemp(fmt.Errorf("failed to close temp file %q: %w", tmpFileName, err))
 	}
-	if err := os.Rename(tmpFileName, fileName); err != nil {
+
+	if opts.Backup {
+		if err := backupExistingFile(fileName, mode); err != nil {
+			os.Remove(tmpFileName)
+			return err
+		}
+	}
+
+	if err := platformAtomicRename(tmpFileName, fileName); err != nil {
 		if removeErr := os.Remove(tmpFileName); removeErr != nil && !os.IsNotExist(removeErr) {
 			return fmt.Errorf("failed to rename temp file %q to %q: %w (also failed to remove temp file: %v)", tmpFileName, fileName, err, removeErr)
 		}
-		return err
+		return fmt.Errorf("failed to rename temp file %q to %q: %w", tmpFileName, fileName, err)
+	}
+
+	if opts.Sync {
+		if err := syncParentDir(fileName); err != nil {
+			return fmt.Errorf("failed to fsync parent directory of %q: %w", fileName, err)
+		}
+	}
+	return nil
+}
+
+// backupExistingFile copies the current contents of fileName to fileName+".bak", doing
+// nothing if fileName doesn't exist yet.
+func backupExistingFile(fileName string, mode os.FileMode) error {
+	existing, err := os.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %q for backup: %w", fileName, err)
+	}
+	if err := os.WriteFile(fileName+".bak", existing, mode); err != nil {
+		return fmt.Errorf("failed to write backup file %q: %w", fileName+".bak", err)
+	}
+	return nil
+}
+
+// AtomicWriteFileWithOptionsFS is AtomicWriteFileWithOptions against an arbitrary FS.
+// For *BasicFS it delegates straight to AtomicWriteFileWithOptions to keep its
+// crash-durability guarantees (fsync + platform-specific atomic rename) exactly as
+// written; other backends get a simpler write-temp-then-rename that honors opts.Sync
+// only when the FS implements syncer (remote filesystems and MemFS generally don't).
+func AtomicWriteFileWithOptionsFS(fsys FS, fileName string, data []byte, opts AtomicWriteFileOptions) error {
+	if _, ok := fsys.(*BasicFS); ok {
+		return AtomicWriteFileWithOptions(fileName, data, opts)
+	}
+	mode := opts.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	tmpFileName := fmt.Sprintf("%s.%s%s", fileName, randSuffix(), TempFileSuffix)
+	if err := fsys.WriteFile(tmpFileName, data, mode); err != nil {
+		return fmt.Errorf("failed to write temp file %q: %w", tmpFileName, err)
+	}
+	if opts.Sync {
+		if s, ok := fsys.(syncer); ok {
+			if err := s.syncFile(tmpFileName); err != nil {
+				fsys.Remove(tmpFileName)
+				return fmt.Errorf("failed to fsync temp file %q: %w", tmpFileName, err)
+			}
+		}
+	}
+	if opts.Backup {
+		if err := backupExistingFileFS(fsys, fileName, mode); err != nil {
+			fsys.Remove(tmpFileName)
+			return err
+		}
+	}
+	if err := fsys.Rename(tmpFileName, fileName); err != nil {
+		fsys.Remove(tmpFileName)
+		return fmt.Errorf("failed to rename temp file %q to %q: %w", tmpFileName, fileName, err)
+	}
+	if opts.Sync {
+		if s, ok := fsys.(syncer); ok {
+			if err := s.syncParentDir(fileName); err != nil {
+				return fmt.Errorf("failed to fsync parent directory of %q: %w", fileName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// backupExistingFileFS is backupExistingFile against an arbitrary FS.
+func backupExistingFileFS(fsys FS, fileName string, mode os.FileMode) error {
+	existing, err := fsys.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %q for backup: %w", fileName, err)
+	}
+	if err := fsys.WriteFile(fileName+".bak", existing, mode); err != nil {
+		return fmt.Errorf("failed to write backup file %q: %w", fileName+".bak", err)
 	}
 	return nil
 }
@@ -218,12 +438,33 @@ type EditSpec struct {
 	OldStr string `json:"old_str"`
 	NewStr string `json:"new_str"`
 	Desc   string `json:"desc,omitempty"`
+
+	// MatchMode controls how OldStr is located: MatchModeExact (default),
+	// MatchModeWhitespace, or MatchModeRegex.
+	MatchMode string `json:"match_mode,omitempty"`
+	// LineRange scopes the search to 1-indexed, inclusive [start, end] lines, so an
+	// otherwise-ambiguous OldStr becomes unique within the window. Zero value (the Go
+	// zero value [2]int{0, 0}) means unscoped.
+	LineRange [2]int `json:"line_range"`
+	// AnchorBefore/AnchorAfter further scope the search to the region of content after
+	// the first exact match of AnchorBefore and/or before the first exact match of
+	// AnchorAfter, applied after LineRange.
+	AnchorBefore string `json:"anchor_before,omitempty"`
+	AnchorAfter  string `json:"anchor_after,omitempty"`
 }
 
 type EditResult struct {
 	Applied bool   `json:"applied"`
 	Desc    string `json:"desc"`
 	Error   string `json:"error,omitempty"`
+
+	// Offset/Length are the byte range in the content *before* this edit that was
+	// replaced, and LineStart/LineEnd (1-indexed, inclusive) the corresponding line
+	// range, so callers (agents, UIs) can render the change without re-diffing.
+	Offset    int `json:"offset,omitempty"`
+	Length    int `json:"length,omitempty"`
+	LineStart int `json:"line_start,omitempty"`
+	LineEnd   int `json:"line_end,omitempty"`
 }
 
 // applyEdit applies a single edit to the content and returns the modified content and result.
@@ -236,26 +477,47 @@ func applyEdit(content []byte, edit EditSpec, index int) ([]byte, EditResult) {
 	}
 
 	if edit.OldStr == "" {
+		if len(content) == 0 {
+			// Inserting into an empty file is unambiguous even though OldStr is empty:
+			// there's no other content it could match against.
+			result.Applied = true
+			result.LineStart = 1
+			result.LineEnd = 1
+			return []byte(edit.NewStr), result
+		}
 		result.Applied = false
 		result.Error = "old_str cannot be empty"
 		return content, result
 	}
 
-	oldBytes := []byte(edit.OldStr)
-	count := bytes.Count(content, oldBytes)
-	if count == 0 {
+	windowStart, windowEnd, err := resolveWindow(content, edit)
+	if err != nil {
 		result.Applied = false
-		result.Error = "old_str not found in file"
+		result.Error = err.Error()
 		return content, result
 	}
-	if count > 1 {
+	offset, length, err := findMatch(content, windowStart, windowEnd, edit)
+	if err != nil {
 		result.Applied = false
-		result.Error = fmt.Sprintf("old_str appears %d times, must appear exactly once", count)
+		result.Error = err.Error()
 		return content, result
 	}
 
-	modifiedContent := bytes.Replace(content, oldBytes, []byte(edit.NewStr), 1)
+	modifiedContent := make([]byte, 0, len(content)-length+len(edit.NewStr))
+	modifiedContent = append(modifiedContent, content[:offset]...)
+	modifiedContent = append(modifiedContent, edit.NewStr...)
+	modifiedContent = append(modifiedContent, content[offset+length:]...)
+
 	result.Applied = true
+	result.Offset = offset
+	result.Length = length
+	result.LineStart = lineNumberAt(content, offset)
+	result.LineEnd = lineNumberAt(content, offset+length)
+	if length > 0 && content[offset+length-1] == '\n' {
+		// The match ends exactly at a line boundary; attribute LineEnd to the line that
+		// was actually replaced rather than the one after it.
+		result.LineEnd--
+	}
 	return modifiedContent, result
 }
 
@@ -303,7 +565,13 @@ func ApplyEditsPartial(originalContent []byte, edits []EditSpec) ([]byte, []Edit
 }
 
 func ReplaceInFile(filePath string, edits []EditSpec) error {
-	fileInfo, err := os.Stat(filePath)
+	return ReplaceInFileFS(&BasicFS{}, filePath, edits)
+}
+
+// ReplaceInFileFS is ReplaceInFile against an arbitrary FS, so edit tooling works the
+// same way against a remote pane's files or an in-memory test tree.
+func ReplaceInFileFS(fsys FS, filePath string, edits []EditSpec) error {
+	fileInfo, err := fsys.Stat(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to stat file: %w", err)
 	}
@@ -316,7 +584,7 @@ func ReplaceInFile(filePath string, edits []EditSpec) error {
 		return fmt.Errorf("file too large for editing: %d bytes (max: %d)", fileInfo.Size(), MaxEditFileSize)
 	}
 
-	contents, err := os.ReadFile(filePath)
+	contents, err := fsys.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
@@ -326,7 +594,7 @@ func ReplaceInFile(filePath string, edits []EditSpec) error {
 		return err
 	}
 
-	if err := os.WriteFile(filePath, modifiedContents, fileInfo.Mode()); err != nil {
+	if err := fsys.WriteFile(filePath, modifiedContents, fileInfo.Mode()); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -336,7 +604,12 @@ func ReplaceInFile(filePath string, edits []EditSpec) error {
 // ReplaceInFilePartial applies edits incrementally up to the first failure.
 // Returns the results for each edit and writes the partially modified content.
 func ReplaceInFilePartial(filePath string, edits []EditSpec) ([]EditResult, error) {
-	fileInfo, err := os.Stat(filePath)
+	return ReplaceInFilePartialFS(&BasicFS{}, filePath, edits)
+}
+
+// ReplaceInFilePartialFS is ReplaceInFilePartial against an arbitrary FS.
+func ReplaceInFilePartialFS(fsys FS, filePath string, edits []EditSpec) ([]EditResult, error) {
+	fileInfo, err := fsys.Stat(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to stat file: %w", err)
 	}
@@ -349,14 +622,14 @@ func ReplaceInFilePartial(filePath string, edits []EditSpec) ([]EditResult, erro
 		return nil, fmt.Errorf("file too large for editing: %d bytes (max: %d)", fileInfo.Size(), MaxEditFileSize)
 	}
 
-	contents, err := os.ReadFile(filePath)
+	contents, err := fsys.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	modifiedContents, results := ApplyEditsPartial(contents, edits)
 
-	if err := os.WriteFile(filePath, modifiedContents, fileInfo.Mode()); err != nil {
+	if err := fsys.WriteFile(filePath, modifiedContents, fileInfo.Mode()); err != nil {
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -1,11 +1,25 @@
 package fileutil
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 )
 
+// leftoverTempFiles lists the entries in dir matching base+".*"+TempFileSuffix, the
+// randomized naming os.CreateTemp gives temp files in AtomicWriteFileWithOptions, so
+// tests can assert no temp file was left behind without knowing its random suffix.
+func leftoverTempFiles(t *testing.T, dir, base string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, base+".*"+TempFileSuffix))
+	if err != nil {
+		t.Fatalf("Glob failed: %v", err)
+	}
+	return matches
+}
+
 func TestAtomicWriteFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	fileName := filepath.Join(tmpDir, "settings.json")
@@ -22,8 +36,8 @@ func TestAtomicWriteFile(t *testing.T) {
 	if string(data) != `{"key":"value"}` {
 		t.Fatalf("unexpected file contents: %q", string(data))
 	}
-	if _, err := os.Stat(fileName + TempFileSuffix); !os.IsNotExist(err) {
-		t.Fatalf("temporary file should not exist, stat err: %v", err)
+	if leftover := leftoverTempFiles(t, tmpDir, "settings.json"); len(leftover) != 0 {
+		t.Fatalf("temporary file should not exist, found: %v", leftover)
 	}
 }
 
@@ -39,7 +53,242 @@ func TestAtomicWriteFileRenameErrorCleansTempFile(t *testing.T) {
 	if err == nil {
 		t.Fatalf("AtomicWriteFile expected error")
 	}
-	if _, statErr := os.Stat(fileName + TempFileSuffix); !os.IsNotExist(statErr) {
-		t.Fatalf("temporary file should be removed on rename error, stat err: %v", statErr)
+	if leftover := leftoverTempFiles(t, tmpDir, "settings.json"); len(leftover) != 0 {
+		t.Fatalf("temporary file should be removed on rename error, found: %v", leftover)
+	}
+}
+
+func TestAtomicWriteFileCreateErrorCleansTempFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	// no such parent directory, so the initial OpenFile of the temp file fails.
+	fileName := filepath.Join(tmpDir, "missing", "settings.json")
+
+	err := AtomicWriteFileWithOptions(fileName, []byte(`{}`), AtomicWriteFileOptions{Sync: true})
+	if err == nil {
+		t.Fatalf("expected error for missing parent directory")
+	}
+	if leftover := leftoverTempFiles(t, tmpDir, "settings.json"); len(leftover) != 0 {
+		t.Fatalf("temporary file should not exist, found: %v", leftover)
+	}
+}
+
+func TestAtomicWriteFileBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+	fileName := filepath.Join(tmpDir, "settings.json")
+
+	if err := AtomicWriteFile(fileName, []byte("old"), 0644); err != nil {
+		t.Fatalf("initial AtomicWriteFile failed: %v", err)
+	}
+	err := AtomicWriteFileWithOptions(fileName, []byte("new"), AtomicWriteFileOptions{Sync: true, Mode: 0644, Backup: true})
+	if err != nil {
+		t.Fatalf("AtomicWriteFileWithOptions failed: %v", err)
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil || string(data) != "new" {
+		t.Fatalf("unexpected file contents: %q, err: %v", data, err)
+	}
+	bakData, err := os.ReadFile(fileName + ".bak")
+	if err != nil || string(bakData) != "old" {
+		t.Fatalf("expected backup of previous contents, got %q, err: %v", bakData, err)
+	}
+}
+
+func TestAtomicWriteFileNoBackupWhenFileMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+	fileName := filepath.Join(tmpDir, "settings.json")
+
+	err := AtomicWriteFileWithOptions(fileName, []byte("first"), AtomicWriteFileOptions{Sync: true, Backup: true})
+	if err != nil {
+		t.Fatalf("AtomicWriteFileWithOptions failed: %v", err)
+	}
+	if _, statErr := os.Stat(fileName + ".bak"); !os.IsNotExist(statErr) {
+		t.Fatalf("expected no backup file when there was nothing to back up, stat err: %v", statErr)
+	}
+}
+
+func TestAtomicWriteFileConcurrentWriters(t *testing.T) {
+	tmpDir := t.TempDir()
+	fileName := filepath.Join(tmpDir, "settings.json")
+
+	const numWriters = 8
+	var wg sync.WaitGroup
+	errs := make([]error, numWriters)
+	for i := 0; i < numWriters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = AtomicWriteFile(fileName, []byte(fmt.Sprintf("writer-%d", i)), 0644)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("writer %d failed: %v", i, err)
+		}
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	found := false
+	for i := 0; i < numWriters; i++ {
+		if string(data) == fmt.Sprintf("writer-%d", i) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("final contents %q don't match any writer's full payload (torn write)", data)
+	}
+	if leftover := leftoverTempFiles(t, tmpDir, "settings.json"); len(leftover) != 0 {
+		t.Fatalf("no temp file should remain after concurrent writers finish, found: %v", leftover)
+	}
+}
+
+func TestAtomicWriteFileWithOptionsFSUsesBasicFSFastPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	fileName := filepath.Join(tmpDir, "settings.json")
+
+	err := AtomicWriteFileWithOptionsFS(&BasicFS{}, fileName, []byte(`{"key":"value"}`), AtomicWriteFileOptions{Sync: true, Mode: 0644})
+	if err != nil {
+		t.Fatalf("AtomicWriteFileWithOptionsFS failed: %v", err)
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil || string(data) != `{"key":"value"}` {
+		t.Fatalf("unexpected file contents: %q, err: %v", data, err)
+	}
+}
+
+func TestAtomicWriteFileWithOptionsFSMemFS(t *testing.T) {
+	m := NewMemFS()
+	err := AtomicWriteFileWithOptionsFS(m, "/settings.json", []byte("first"), AtomicWriteFileOptions{Sync: true, Mode: 0644})
+	if err != nil {
+		t.Fatalf("AtomicWriteFileWithOptionsFS failed: %v", err)
+	}
+	data, err := m.ReadFile("/settings.json")
+	if err != nil || string(data) != "first" {
+		t.Fatalf("unexpected file contents: %q, err: %v", data, err)
+	}
+
+	err = AtomicWriteFileWithOptionsFS(m, "/settings.json", []byte("second"), AtomicWriteFileOptions{Mode: 0644, Backup: true})
+	if err != nil {
+		t.Fatalf("AtomicWriteFileWithOptionsFS (update) failed: %v", err)
+	}
+	data, err = m.ReadFile("/settings.json")
+	if err != nil || string(data) != "second" {
+		t.Fatalf("unexpected file contents after update: %q, err: %v", data, err)
+	}
+	bakData, err := m.ReadFile("/settings.json.bak")
+	if err != nil || string(bakData) != "first" {
+		t.Fatalf("expected backup of previous contents, got %q, err: %v", bakData, err)
+	}
+}
+
+func TestDetectMimeTypeFSMemFS(t *testing.T) {
+	m := NewMemFS()
+	m.PutFile("/notes.json", []byte(`{"a":1}`), 0644)
+	if got := DetectMimeTypeFS(m, "/notes.json", nil, false); got != "application/json" {
+		t.Fatalf("expected application/json, got %q", got)
+	}
+}
+
+func TestReplaceInFileFSMemFS(t *testing.T) {
+	m := NewMemFS()
+	m.PutFile("/config.txt", []byte("name=old"), 0644)
+	err := ReplaceInFileFS(m, "/config.txt", []EditSpec{{OldStr: "old", NewStr: "new"}})
+	if err != nil {
+		t.Fatalf("ReplaceInFileFS failed: %v", err)
+	}
+	data, err := m.ReadFile("/config.txt")
+	if err != nil || string(data) != "name=new" {
+		t.Fatalf("unexpected file contents: %q, err: %v", data, err)
+	}
+}
+
+// syncFailMemFS wraps MemFS with a syncer implementation whose failures and invocations
+// are injectable/observable, so tests can exercise AtomicWriteFileWithOptionsFS's sync
+// failure and parent-dir fsync paths without a real disk.
+type syncFailMemFS struct {
+	*MemFS
+	failSyncFile      bool
+	failSyncParentDir bool
+	syncedFiles       []string
+	syncedParentDirs  []string
+}
+
+func (f *syncFailMemFS) syncFile(name string) error {
+	f.syncedFiles = append(f.syncedFiles, name)
+	if f.failSyncFile {
+		return fmt.Errorf("injected sync failure for %q", name)
+	}
+	return nil
+}
+
+func (f *syncFailMemFS) syncParentDir(name string) error {
+	f.syncedParentDirs = append(f.syncedParentDirs, name)
+	if f.failSyncParentDir {
+		return fmt.Errorf("injected parent dir sync failure for %q", name)
+	}
+	return nil
+}
+
+func TestAtomicWriteFileWithOptionsFSSyncFailureCleansTempFile(t *testing.T) {
+	m := &syncFailMemFS{MemFS: NewMemFS(), failSyncFile: true}
+	err := AtomicWriteFileWithOptionsFS(m, "/settings.json", []byte("first"), AtomicWriteFileOptions{Sync: true, Mode: 0644})
+	if err == nil {
+		t.Fatalf("expected error when syncFile fails")
+	}
+	if len(m.syncedFiles) == 0 {
+		t.Fatalf("expected syncFile to have been called")
+	}
+	if _, statErr := m.Stat(m.syncedFiles[0]); !os.IsNotExist(statErr) {
+		t.Fatalf("temp file %q should have been removed after a sync failure, stat err: %v", m.syncedFiles[0], statErr)
+	}
+	if _, statErr := m.Stat("/settings.json"); !os.IsNotExist(statErr) {
+		t.Fatalf("file should not have been renamed into place after a sync failure, stat err: %v", statErr)
+	}
+}
+
+func TestAtomicWriteFileWithOptionsFSSyncsParentDirOnSuccess(t *testing.T) {
+	m := &syncFailMemFS{MemFS: NewMemFS()}
+	err := AtomicWriteFileWithOptionsFS(m, "/settings.json", []byte("first"), AtomicWriteFileOptions{Sync: true, Mode: 0644})
+	if err != nil {
+		t.Fatalf("AtomicWriteFileWithOptionsFS failed: %v", err)
+	}
+	if len(m.syncedParentDirs) == 0 || m.syncedParentDirs[0] != "/settings.json" {
+		t.Fatalf("expected the parent directory of /settings.json to be fsynced, got: %v", m.syncedParentDirs)
+	}
+}
+
+func TestAtomicWriteFileWithOptionsFSParentDirSyncFailurePropagates(t *testing.T) {
+	m := &syncFailMemFS{MemFS: NewMemFS(), failSyncParentDir: true}
+	err := AtomicWriteFileWithOptionsFS(m, "/settings.json", []byte("first"), AtomicWriteFileOptions{Sync: true, Mode: 0644})
+	if err == nil {
+		t.Fatalf("expected error when syncParentDir fails")
+	}
+	// the rename already happened by the time the parent-dir fsync runs, so the write
+	// itself isn't rolled back on this failure.
+	data, readErr := m.ReadFile("/settings.json")
+	if readErr != nil || string(data) != "first" {
+		t.Fatalf("expected the file to still be written despite the parent-dir sync failure: %q, err: %v", data, readErr)
+	}
+}
+
+func TestReplaceInFilePartialFSMemFS(t *testing.T) {
+	m := NewMemFS()
+	m.PutFile("/config.txt", []byte("name=old"), 0644)
+	results, err := ReplaceInFilePartialFS(m, "/config.txt", []EditSpec{
+		{OldStr: "name=old", NewStr: "name=new"},
+		{OldStr: "missing", NewStr: "x"},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceInFilePartialFS failed: %v", err)
+	}
+	if !results[0].Applied || results[1].Applied {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	data, err := m.ReadFile("/config.txt")
+	if err != nil || string(data) != "name=new" {
+		t.Fatalf("unexpected file contents: %q, err: %v", data, err)
 	}
 }
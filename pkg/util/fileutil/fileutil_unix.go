@@ -0,0 +1,27 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// platformAtomicRename renames src over dst. On Unix, os.Rename is already atomic.
+func platformAtomicRename(src, dst string) error {
+	return os.Rename(src, dst)
+}
+
+// syncParentDir fsyncs the parent directory of path so a preceding rename within it is
+// durable across a crash, not just reflected in the page cache.
+func syncParentDir(path string) error {
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
@@ -0,0 +1,31 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package fileutil
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// platformAtomicRename renames src over dst using MoveFileEx, since os.Rename isn't
+// always atomic on Windows when dst already exists (some filesystems fall back to a
+// non-atomic copy+delete).
+func platformAtomicRename(src, dst string) error {
+	srcPtr, err := windows.UTF16PtrFromString(src)
+	if err != nil {
+		return err
+	}
+	dstPtr, err := windows.UTF16PtrFromString(dst)
+	if err != nil {
+		return err
+	}
+	return windows.MoveFileEx(srcPtr, dstPtr, windows.MOVEFILE_REPLACE_EXISTING|windows.MOVEFILE_WRITE_THROUGH)
+}
+
+// syncParentDir is a no-op on Windows: MoveFileEx with MOVEFILE_WRITE_THROUGH already
+// waits for the rename to hit disk, and Windows doesn't support fsync-ing a directory handle.
+func syncParentDir(path string) error {
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"io"
+	"io/fs"
+	"os"
+)
+
+// FS abstracts the filesystem operations fileutil needs so mime detection, atomic
+// writes, and edit application work the same way against the local disk, a remote SFTP
+// connection, or an in-memory tree in tests. It embeds io/fs.FS for read access so
+// callers can also hand any FS-accepting helper a zipfs, embed.FS, or similar through
+// its Open method alone.
+type FS interface {
+	fs.FS
+
+	// URI identifies this filesystem instance, e.g. "local" or "wsh://<connection>".
+	URI() string
+	// Type is a short machine-readable backend kind, e.g. "local", "sftp", "mem".
+	Type() string
+
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	Create(name string) (io.WriteCloser, error)
+	Rename(oldname, newname string) error
+	Remove(name string) error
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	Symlink(oldname, newname string) error
+}
+
+// syncer is implemented by FS backends that can fsync a just-written file and the
+// parent directory it was renamed into, so AtomicWriteFileWithOptionsFS can honor
+// opts.Sync. Backends without a meaningful notion of fsync (MemFS, remote filesystems
+// with no local page cache to flush) simply don't implement it, and the sync is skipped.
+type syncer interface {
+	syncFile(name string) error
+	syncParentDir(name string) error
+}
+
+// BasicFS is the FS backed by the local disk via the os package.
+type BasicFS struct{}
+
+// NewBasicFS returns an FS backed by the local disk.
+func NewBasicFS() *BasicFS {
+	return &BasicFS{}
+}
+
+func (*BasicFS) URI() string  { return "local" }
+func (*BasicFS) Type() string { return "local" }
+
+func (*BasicFS) Open(name string) (fs.File, error)          { return os.Open(name) }
+func (*BasicFS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (*BasicFS) Lstat(name string) (fs.FileInfo, error)     { return os.Lstat(name) }
+func (*BasicFS) Create(name string) (io.WriteCloser, error) { return os.Create(name) }
+func (*BasicFS) Remove(name string) error                   { return os.Remove(name) }
+func (*BasicFS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (*BasicFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+func (*BasicFS) Symlink(oldname, newname string) error { return os.Symlink(oldname, newname) }
+
+// Rename uses platformAtomicRename so BasicFS gets the same Windows MOVEFILE_WRITE_THROUGH
+// treatment as AtomicWriteFileWithOptions, rather than a plain os.Rename.
+func (*BasicFS) Rename(oldname, newname string) error {
+	return platformAtomicRename(oldname, newname)
+}
+
+func (*BasicFS) syncFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+func (*BasicFS) syncParentDir(name string) error {
+	return syncParentDir(name)
+}
@@ -0,0 +1,171 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory FS for tests that exercise mime detection, atomic writes, or
+// edit application without touching real files. Paths are treated as flat keys (slash-
+// separated, like io/fs) rather than resolved against a directory tree, which is
+// sufficient for every fileutil helper: none of them list directories.
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+}
+
+type memFile struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// NewMemFS returns an empty in-memory FS.
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string]*memFile)}
+}
+
+func (*MemFS) URI() string  { return "mem" }
+func (*MemFS) Type() string { return "mem" }
+
+// PutFile seeds name with data and mode, as if it had been written before the test
+// started. It's the MemFS equivalent of writing a fixture file to a temp dir.
+func (m *MemFS) PutFile(name string, data []byte, mode fs.FileMode) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = &memFile{data: append([]byte(nil), data...), mode: mode, modTime: time.Now()}
+}
+
+func (m *MemFS) Open(name string) (fs.File, error) {
+	m.mu.Lock()
+	f, ok := m.files[name]
+	m.mu.Unlock()
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memOpenFile{name: name, data: f.data, mode: f.mode, modTime: f.modTime}, nil
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return memFileInfo{name: path.Base(name), file: f}, nil
+}
+
+// Lstat never follows symlinks because MemFS doesn't model them; it's equivalent to Stat.
+func (m *MemFS) Lstat(name string) (fs.FileInfo, error) {
+	return m.Stat(name)
+}
+
+func (m *MemFS) Create(name string) (io.WriteCloser, error) {
+	return &memWriteFile{fsys: m, name: name, mode: 0644}, nil
+}
+
+func (m *MemFS) Rename(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[oldname]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fs.ErrNotExist}
+	}
+	m.files[newname] = f
+	delete(m.files, oldname)
+	return nil
+}
+
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.PutFile(name, data, perm)
+	return nil
+}
+
+// Symlink records newname as an alias that reads back oldname's current contents; since
+// MemFS doesn't model links, a later write to newname just overwrites the alias.
+func (m *MemFS) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[oldname]
+	if !ok {
+		return &fs.PathError{Op: "symlink", Path: oldname, Err: fs.ErrNotExist}
+	}
+	m.files[newname] = f
+	return nil
+}
+
+type memWriteFile struct {
+	fsys *MemFS
+	name string
+	mode fs.FileMode
+	buf  bytes.Buffer
+}
+
+func (w *memWriteFile) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *memWriteFile) Close() error {
+	w.fsys.PutFile(w.name, w.buf.Bytes(), w.mode)
+	return nil
+}
+
+type memOpenFile struct {
+	name    string
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+	reader  *bytes.Reader
+}
+
+func (f *memOpenFile) Read(p []byte) (int, error) {
+	if f.reader == nil {
+		f.reader = bytes.NewReader(f.data)
+	}
+	return f.reader.Read(p)
+}
+
+func (f *memOpenFile) Close() error { return nil }
+
+func (f *memOpenFile) Stat() (fs.FileInfo, error) {
+	return memFileInfo{name: path.Base(f.name), file: &memFile{data: f.data, mode: f.mode, modTime: f.modTime}}, nil
+}
+
+type memFileInfo struct {
+	name string
+	file *memFile
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.file.data)) }
+func (i memFileInfo) Mode() fs.FileMode  { return i.file.mode }
+func (i memFileInfo) ModTime() time.Time { return i.file.modTime }
+func (i memFileInfo) IsDir() bool        { return i.file.mode.IsDir() }
+func (i memFileInfo) Sys() any           { return nil }
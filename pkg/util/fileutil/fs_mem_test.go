@@ -0,0 +1,63 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"testing"
+)
+
+func TestMemFSWriteStatReadRoundTrip(t *testing.T) {
+	m := NewMemFS()
+	if err := m.WriteFile("/greeting.txt", []byte("hello"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	fi, err := m.Stat("/greeting.txt")
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if fi.Size() != 5 {
+		t.Fatalf("expected size 5, got %d", fi.Size())
+	}
+	data, err := m.ReadFile("/greeting.txt")
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("ReadFile = %q, %v", data, err)
+	}
+}
+
+func TestMemFSRenameMovesContent(t *testing.T) {
+	m := NewMemFS()
+	m.PutFile("/a.txt", []byte("content"), 0644)
+	if err := m.Rename("/a.txt", "/b.txt"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if _, err := m.Stat("/a.txt"); err == nil {
+		t.Fatal("expected /a.txt to no longer exist after rename")
+	}
+	data, err := m.ReadFile("/b.txt")
+	if err != nil || string(data) != "content" {
+		t.Fatalf("ReadFile(/b.txt) = %q, %v", data, err)
+	}
+}
+
+func TestMemFSRemoveMissingFileErrors(t *testing.T) {
+	m := NewMemFS()
+	if err := m.Remove("/nope.txt"); err == nil {
+		t.Fatal("expected an error removing a file that doesn't exist")
+	}
+}
+
+func TestMemFSOpenReadsContent(t *testing.T) {
+	m := NewMemFS()
+	m.PutFile("/a.txt", []byte("stream me"), 0644)
+	f, err := m.Open("/a.txt")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+	buf := make([]byte, 9)
+	n, err := f.Read(buf)
+	if err != nil || string(buf[:n]) != "stream me" {
+		t.Fatalf("Read = %q, %v", buf[:n], err)
+	}
+}
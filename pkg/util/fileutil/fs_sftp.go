@@ -0,0 +1,67 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/pkg/sftp"
+)
+
+// SFTPFS is the FS backed by a connection to a remote machine over SFTP, used for Wave's
+// remote (wsh) connections so the same mime detection, atomic write, and edit helpers
+// that work on local panes also work against a remote pane's files.
+type SFTPFS struct {
+	client *sftp.Client
+	uri    string
+}
+
+// NewSFTPFS wraps an already-dialed *sftp.Client. uri should identify the connection
+// (e.g. "wsh://user@host") for logging and error messages.
+func NewSFTPFS(client *sftp.Client, uri string) *SFTPFS {
+	return &SFTPFS{client: client, uri: uri}
+}
+
+func (s *SFTPFS) URI() string { return s.uri }
+func (*SFTPFS) Type() string  { return "sftp" }
+
+func (s *SFTPFS) Open(name string) (fs.File, error) { return s.client.Open(name) }
+
+func (s *SFTPFS) Stat(name string) (fs.FileInfo, error)  { return s.client.Stat(name) }
+func (s *SFTPFS) Lstat(name string) (fs.FileInfo, error) { return s.client.Lstat(name) }
+
+func (s *SFTPFS) Create(name string) (io.WriteCloser, error) { return s.client.Create(name) }
+
+func (s *SFTPFS) Remove(name string) error { return s.client.Remove(name) }
+
+func (s *SFTPFS) Symlink(oldname, newname string) error { return s.client.Symlink(oldname, newname) }
+
+// Rename uses PosixRename, which atomically replaces newname if it already exists (the
+// semantics AtomicWriteFileWithOptionsFS needs); plain SFTP rename fails in that case.
+func (s *SFTPFS) Rename(oldname, newname string) error {
+	return s.client.PosixRename(oldname, newname)
+}
+
+func (s *SFTPFS) ReadFile(name string) ([]byte, error) {
+	f, err := s.client.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func (s *SFTPFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	f, err := s.client.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Chmod(perm)
+}
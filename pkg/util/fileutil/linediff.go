@@ -0,0 +1,198 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffToEditSpecs diffs original against edited line-by-line and returns the minimal set
+// of EditSpec hunks that reproduce the change through ApplyEdits: each hunk's OldStr is
+// grown with surrounding context lines until it occurs exactly once in original (the
+// single-match invariant applyEdit enforces). If no amount of context makes every hunk
+// unique, it falls back to a single whole-file-replacement EditSpec.
+func diffToEditSpecs(original, edited []byte, contextLines int) []EditSpec {
+	origLines := splitKeepEnds(original)
+	editLines := splitKeepEnds(edited)
+
+	hunks := mergeAdjacentHunks(lineDiffHunks(origLines, editLines), contextLines)
+
+	specs := make([]EditSpec, 0, len(hunks))
+	for _, h := range hunks {
+		spec, ok := hunkToEditSpec(origLines, editLines, h, contextLines, original)
+		if !ok {
+			return []EditSpec{{
+				OldStr: string(original),
+				NewStr: string(edited),
+				Desc:   "whole file replacement (no unique minimal hunk found)",
+			}}
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// splitKeepEnds splits data into lines, each retaining its trailing "\n" (the last line
+// won't have one if data doesn't end in a newline), so joining the lines back together
+// reproduces data exactly.
+func splitKeepEnds(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.SplitAfter(string(data), "\n")
+}
+
+// lineHunk is a contiguous range of changed lines: original[OStart:OEnd] is replaced by
+// edited[EStart:EEnd].
+type lineHunk struct {
+	OStart, OEnd int
+	EStart, EEnd int
+}
+
+// lineDiffHunks runs an LCS-based line diff and returns each maximal run of
+// inserted/deleted lines as a hunk, in order. It's O(n*m) time and space, which is fine
+// for the hand-edited-file-sized diffs EditInEditor deals with.
+func lineDiffHunks(a, b []string) []lineHunk {
+	n, m := len(a), len(b)
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// Backtrack the LCS table into a flat equal/delete/insert op sequence, then group
+	// maximal runs of non-equal ops into hunks.
+	type opKind int
+	const (
+		opEqual opKind = iota
+		opDelete
+		opInsert
+	)
+	var ops []opKind
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, opEqual)
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, opDelete)
+			i++
+		default:
+			ops = append(ops, opInsert)
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, opDelete)
+	}
+	for ; j < m; j++ {
+		ops = append(ops, opInsert)
+	}
+
+	var hunks []lineHunk
+	oi, ei := 0, 0
+	for k := 0; k < len(ops); {
+		if ops[k] == opEqual {
+			oi++
+			ei++
+			k++
+			continue
+		}
+		oStart, eStart := oi, ei
+		for k < len(ops) && ops[k] != opEqual {
+			if ops[k] == opDelete {
+				oi++
+			} else {
+				ei++
+			}
+			k++
+		}
+		hunks = append(hunks, lineHunk{OStart: oStart, OEnd: oi, EStart: eStart, EEnd: ei})
+	}
+	return hunks
+}
+
+// mergeAdjacentHunks coalesces hunks separated by a run of contextLines*2 or fewer
+// unchanged lines, since expanding each hunk's context independently would otherwise
+// produce overlapping OldStr windows that can't be applied in sequence.
+func mergeAdjacentHunks(hunks []lineHunk, contextLines int) []lineHunk {
+	if len(hunks) == 0 {
+		return hunks
+	}
+	merged := []lineHunk{hunks[0]}
+	for _, h := range hunks[1:] {
+		last := &merged[len(merged)-1]
+		if h.OStart-last.OEnd <= 2*contextLines {
+			last.OEnd = h.OEnd
+			last.EEnd = h.EEnd
+			continue
+		}
+		merged = append(merged, h)
+	}
+	return merged
+}
+
+// hunkToEditSpec grows h with up to contextLines of surrounding unchanged lines (more,
+// if needed, up to the whole file) until its OldStr is unique in original, returning
+// false if even the full file isn't enough (which can only happen for a hunk that spans
+// the entire file already, since growing further is a no-op).
+func hunkToEditSpec(origLines, editLines []string, h lineHunk, contextLines int, original []byte) (EditSpec, bool) {
+	if len(origLines) == 0 {
+		// A pure insertion into an empty file has no context to grow into: OldStr would
+		// stay "" no matter how much we extend the window, which applyEdit rejects
+		// outright. Whole-file replacement is unambiguous here anyway, since there's
+		// nothing else in the file it could be confused with.
+		return EditSpec{
+			OldStr: string(original),
+			NewStr: strings.Join(editLines, ""),
+			Desc:   "insert into empty file",
+		}, true
+	}
+	for extra := contextLines; ; extra += contextLines {
+		oStart := h.OStart - extra
+		if oStart < 0 {
+			oStart = 0
+		}
+		oEnd := h.OEnd + extra
+		if oEnd > len(origLines) {
+			oEnd = len(origLines)
+		}
+		eStart := h.EStart - extra
+		if eStart < 0 {
+			eStart = 0
+		}
+		eEnd := h.EEnd + extra
+		if eEnd > len(editLines) {
+			eEnd = len(editLines)
+		}
+
+		oldStr := strings.Join(origLines[oStart:oEnd], "")
+		newStr := strings.Join(editLines[eStart:eEnd], "")
+
+		if bytes.Count(original, []byte(oldStr)) == 1 {
+			return EditSpec{
+				OldStr: oldStr,
+				NewStr: newStr,
+				Desc:   fmt.Sprintf("lines %d-%d", h.OStart+1, h.OEnd),
+			}, true
+		}
+		if oStart == 0 && oEnd == len(origLines) {
+			return EditSpec{}, false
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import "testing"
+
+func TestDiffToEditSpecsSingleLineChange(t *testing.T) {
+	original := []byte("line1\nline2\nline3\n")
+	edited := []byte("line1\nCHANGED\nline3\n")
+
+	specs := diffToEditSpecs(original, edited, 2)
+	if len(specs) != 1 {
+		t.Fatalf("expected exactly one hunk, got %d: %+v", len(specs), specs)
+	}
+	applied, err := ApplyEdits(original, specs)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(applied) != string(edited) {
+		t.Fatalf("applying the diff didn't reproduce the edit: got %q, want %q", applied, edited)
+	}
+}
+
+func TestDiffToEditSpecsMultipleSeparateHunks(t *testing.T) {
+	original := []byte("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n")
+	edited := []byte("A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n")
+
+	specs := diffToEditSpecs(original, edited, 1)
+	if len(specs) != 2 {
+		t.Fatalf("expected two separate hunks for far-apart edits, got %d: %+v", len(specs), specs)
+	}
+	applied, err := ApplyEdits(original, specs)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(applied) != string(edited) {
+		t.Fatalf("applying the diff didn't reproduce the edit: got %q, want %q", applied, edited)
+	}
+}
+
+func TestDiffToEditSpecsNearbyHunksMerge(t *testing.T) {
+	original := []byte("a\nb\nc\nd\ne\n")
+	edited := []byte("A\nb\nc\nD\ne\n")
+
+	specs := diffToEditSpecs(original, edited, 2)
+	if len(specs) != 1 {
+		t.Fatalf("expected nearby edits to merge into one hunk, got %d: %+v", len(specs), specs)
+	}
+	applied, err := ApplyEdits(original, specs)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(applied) != string(edited) {
+		t.Fatalf("applying the diff didn't reproduce the edit: got %q, want %q", applied, edited)
+	}
+}
+
+func TestDiffToEditSpecsGrowsContextForUniqueness(t *testing.T) {
+	// "b" appears three times, so a zero-context hunk around the changed "b" would be
+	// ambiguous; the hunk must grow until the surrounding context disambiguates it.
+	original := []byte("x\nb\ny\nb\nz\nb\nw\n")
+	edited := []byte("x\nb\ny\nCHANGED\nz\nb\nw\n")
+
+	specs := diffToEditSpecs(original, edited, 1)
+	applied, err := ApplyEdits(original, specs)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(applied) != string(edited) {
+		t.Fatalf("applying the diff didn't reproduce the edit: got %q, want %q", applied, edited)
+	}
+}
+
+func TestDiffToEditSpecsNoChange(t *testing.T) {
+	original := []byte("same\n")
+	specs := diffToEditSpecs(original, original, 2)
+	if len(specs) != 0 {
+		t.Fatalf("expected no hunks for identical content, got %+v", specs)
+	}
+}
+
+func TestDiffToEditSpecsInsertIntoEmptyFile(t *testing.T) {
+	var original []byte
+	edited := []byte("hello world\n")
+
+	specs := diffToEditSpecs(original, edited, 2)
+	if len(specs) != 1 {
+		t.Fatalf("expected exactly one hunk, got %d: %+v", len(specs), specs)
+	}
+	applied, err := ApplyEdits(original, specs)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(applied) != string(edited) {
+		t.Fatalf("applying the diff didn't reproduce the edit: got %q, want %q", applied, edited)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MatchModeExact requires edit.OldStr to appear byte-for-byte, exactly once, in the
+	// search window. This is the default.
+	MatchModeExact = "exact"
+	// MatchModeWhitespace matches line-by-line after collapsing runs of spaces/tabs to
+	// a single space and trimming trailing whitespace, so LLM-authored patches survive
+	// whitespace drift between what the model saw and what's actually on disk.
+	MatchModeWhitespace = "whitespace"
+	// MatchModeRegex treats edit.OldStr as a regular expression (RE2 syntax).
+	MatchModeRegex = "regex"
+)
+
+var whitespaceRunPattern = regexp.MustCompile(`[ \t]+`)
+
+// normalizeWhitespaceLine collapses runs of spaces/tabs to a single space and trims
+// trailing whitespace from a single line (which must not include its trailing newline).
+func normalizeWhitespaceLine(line string) string {
+	return strings.TrimRight(whitespaceRunPattern.ReplaceAllString(line, " "), " \t")
+}
+
+// lineSpan is a line's byte range within some content, End exclusive and including the
+// line's trailing newline, if any.
+type lineSpan struct {
+	Start, End int
+}
+
+// lineSpans returns the byte range of every line in content.
+func lineSpans(content []byte) []lineSpan {
+	var spans []lineSpan
+	start := 0
+	for start < len(content) {
+		idx := bytes.IndexByte(content[start:], '\n')
+		if idx < 0 {
+			spans = append(spans, lineSpan{start, len(content)})
+			break
+		}
+		end := start + idx + 1
+		spans = append(spans, lineSpan{start, end})
+		start = end
+	}
+	return spans
+}
+
+// lineNumberAt returns the 1-indexed line number containing byte offset in content.
+func lineNumberAt(content []byte, offset int) int {
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// resolveWindow narrows the byte range of content a match is allowed to occur in, based
+// on edit.LineRange/AnchorBefore/AnchorAfter. With none of those set it returns the full
+// content range, exactly preserving applyEdit's original whole-file search behavior.
+func resolveWindow(content []byte, edit EditSpec) (start, end int, err error) {
+	start, end = 0, len(content)
+	if edit.LineRange != ([2]int{}) {
+		spans := lineSpans(content)
+		lo, hi := edit.LineRange[0], edit.LineRange[1]
+		if lo < 1 || hi < lo || hi > len(spans) {
+			return 0, 0, fmt.Errorf("line_range %v is out of bounds for a %d-line file", edit.LineRange, len(spans))
+		}
+		start, end = spans[lo-1].Start, spans[hi-1].End
+	}
+	if edit.AnchorBefore != "" {
+		idx := bytes.Index(content[start:end], []byte(edit.AnchorBefore))
+		if idx < 0 {
+			return 0, 0, fmt.Errorf("anchor_before %q not found in search window", edit.AnchorBefore)
+		}
+		start = start + idx + len(edit.AnchorBefore)
+	}
+	if edit.AnchorAfter != "" {
+		idx := bytes.Index(content[start:end], []byte(edit.AnchorAfter))
+		if idx < 0 {
+			return 0, 0, fmt.Errorf("anchor_after %q not found in search window", edit.AnchorAfter)
+		}
+		end = start + idx
+	}
+	return start, end, nil
+}
+
+// findMatch locates edit.OldStr within content[start:end] per edit.MatchMode, returning
+// the absolute (offset, length) of the single match. It errors if OldStr matches zero or
+// more than once, the same single-match invariant plain exact matching has always had.
+func findMatch(content []byte, start, end int, edit EditSpec) (offset, length int, err error) {
+	window := content[start:end]
+	switch edit.MatchMode {
+	case "", MatchModeExact:
+		oldBytes := []byte(edit.OldStr)
+		count := bytes.Count(window, oldBytes)
+		if count == 0 {
+			return 0, 0, fmt.Errorf("old_str not found in file")
+		}
+		if count > 1 {
+			return 0, 0, fmt.Errorf("old_str appears %d times, must appear exactly once", count)
+		}
+		return start + bytes.Index(window, oldBytes), len(oldBytes), nil
+
+	case MatchModeWhitespace:
+		return findWhitespaceMatch(content, start, end, edit.OldStr)
+
+	case MatchModeRegex:
+		re, err := regexp.Compile(edit.OldStr)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid regex old_str: %w", err)
+		}
+		matches := re.FindAllIndex(window, -1)
+		if len(matches) == 0 {
+			return 0, 0, fmt.Errorf("old_str regex matched nothing")
+		}
+		if len(matches) > 1 {
+			return 0, 0, fmt.Errorf("old_str regex matched %d times, must match exactly once", len(matches))
+		}
+		return start + matches[0][0], matches[0][1] - matches[0][0], nil
+
+	default:
+		return 0, 0, fmt.Errorf("unknown match_mode %q", edit.MatchMode)
+	}
+}
+
+// findWhitespaceMatch finds the single contiguous run of lines within content[start:end]
+// whose whitespace-normalized text equals oldStr's, line for line.
+func findWhitespaceMatch(content []byte, start, end int, oldStr string) (offset, length int, err error) {
+	oldLines := splitOldStrLines(oldStr)
+	for i, line := range oldLines {
+		oldLines[i] = normalizeWhitespaceLine(line)
+	}
+	spans := lineSpans(content[start:end])
+
+	matches := 0
+	var matchStart, matchEnd int
+	for i := 0; i+len(oldLines) <= len(spans); i++ {
+		if !whitespaceLinesMatch(content, start, spans[i:i+len(oldLines)], oldLines) {
+			continue
+		}
+		matches++
+		matchStart = start + spans[i].Start
+		matchEnd = start + spans[i+len(oldLines)-1].End
+	}
+	if matches == 0 {
+		return 0, 0, fmt.Errorf("old_str not found (whitespace-normalized) in file")
+	}
+	if matches > 1 {
+		return 0, 0, fmt.Errorf("old_str appears %d times (whitespace-normalized), must appear exactly once", matches)
+	}
+	return matchStart, matchEnd - matchStart, nil
+}
+
+// splitOldStrLines splits s into logical lines the same way lineSpans counts them in
+// file content: a trailing newline ends the last line rather than introducing an extra
+// empty one, so an OldStr like "a\nb\n" is 2 lines, matching lineSpans(content) for a
+// file whose last line is "b\n".
+func splitOldStrLines(s string) []string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" && strings.HasSuffix(s, "\n") {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func whitespaceLinesMatch(content []byte, base int, candidate []lineSpan, wantLines []string) bool {
+	for k, span := range candidate {
+		got := string(content[base+span.Start : base+span.End])
+		got = strings.TrimSuffix(got, "\n")
+		if normalizeWhitespaceLine(got) != wantLines[k] {
+			return false
+		}
+	}
+	return true
+}
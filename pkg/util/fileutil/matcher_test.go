@@ -0,0 +1,119 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import "testing"
+
+func TestApplyEditsReportsOffsetAndLines(t *testing.T) {
+	content := []byte("line1\nline2\nline3\n")
+	modified, err := ApplyEdits(content, []EditSpec{{OldStr: "line2\n", NewStr: "replaced\n"}})
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(modified) != "line1\nreplaced\nline3\n" {
+		t.Fatalf("unexpected result: %q", modified)
+	}
+	_, result := applyEdit(content, EditSpec{OldStr: "line2\n", NewStr: "replaced\n"}, 0)
+	if !result.Applied || result.Offset != 6 || result.Length != 6 || result.LineStart != 2 || result.LineEnd != 2 {
+		t.Fatalf("unexpected result metadata: %+v", result)
+	}
+}
+
+func TestApplyEditEmptyOldStrInsertsIntoEmptyFile(t *testing.T) {
+	modified, result := applyEdit(nil, EditSpec{OldStr: "", NewStr: "hello world\n"}, 0)
+	if !result.Applied {
+		t.Fatalf("expected the edit to apply, got error: %s", result.Error)
+	}
+	if string(modified) != "hello world\n" {
+		t.Fatalf("unexpected result: %q", modified)
+	}
+}
+
+func TestApplyEditEmptyOldStrRejectedForNonEmptyFile(t *testing.T) {
+	_, result := applyEdit([]byte("existing\n"), EditSpec{OldStr: "", NewStr: "hello world\n"}, 0)
+	if result.Applied {
+		t.Fatalf("expected an empty old_str to be rejected for a non-empty file")
+	}
+	if result.Error != "old_str cannot be empty" {
+		t.Fatalf("unexpected error: %q", result.Error)
+	}
+}
+
+func TestApplyEditWhitespaceModeToleratesDrift(t *testing.T) {
+	// Internal run-length differences ("  " vs " ") and trailing whitespace (the tab
+	// before the newline) should be tolerated; leading indentation is a run too, and
+	// both sides have one, so it collapses to the same thing on both sides.
+	content := []byte("func f() {\n    return  1\t\n}\n")
+	edit := EditSpec{
+		OldStr:    "func f() {\n    return 1\n}\n",
+		NewStr:    "func f() {\n    return 2\n}\n",
+		MatchMode: MatchModeWhitespace,
+	}
+	modified, err := ApplyEdits(content, []EditSpec{edit})
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(modified) != "func f() {\n    return 2\n}\n" {
+		t.Fatalf("unexpected result: %q", modified)
+	}
+}
+
+func TestApplyEditWhitespaceModeRejectsAmbiguousMatch(t *testing.T) {
+	content := []byte("a\nb\na\nb\n")
+	edit := EditSpec{OldStr: "a\nb", NewStr: "x\ny", MatchMode: MatchModeWhitespace}
+	if _, err := ApplyEdits(content, []EditSpec{edit}); err == nil {
+		t.Fatal("expected an error for an ambiguous whitespace match")
+	}
+}
+
+func TestApplyEditRegexMode(t *testing.T) {
+	content := []byte("version = \"1.2.3\"\n")
+	edit := EditSpec{OldStr: `version = "\d+\.\d+\.\d+"`, NewStr: `version = "1.2.4"`, MatchMode: MatchModeRegex}
+	modified, err := ApplyEdits(content, []EditSpec{edit})
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(modified) != "version = \"1.2.4\"\n" {
+		t.Fatalf("unexpected result: %q", modified)
+	}
+}
+
+func TestApplyEditRegexModeInvalidPattern(t *testing.T) {
+	edit := EditSpec{OldStr: `(unclosed`, NewStr: "x", MatchMode: MatchModeRegex}
+	if _, err := ApplyEdits([]byte("content"), []EditSpec{edit}); err == nil {
+		t.Fatal("expected an error for an invalid regex")
+	}
+}
+
+func TestApplyEditLineRangeDisambiguates(t *testing.T) {
+	content := []byte("x\nfoo\ny\nfoo\nz\n")
+	// "foo" is ambiguous over the whole file but unique once scoped to lines 1-3.
+	edit := EditSpec{OldStr: "foo", NewStr: "bar", LineRange: [2]int{1, 3}}
+	modified, err := ApplyEdits(content, []EditSpec{edit})
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(modified) != "x\nbar\ny\nfoo\nz\n" {
+		t.Fatalf("unexpected result: %q", modified)
+	}
+}
+
+func TestApplyEditAnchorsDisambiguate(t *testing.T) {
+	content := []byte("section A\nfoo\nsection B\nfoo\n")
+	edit := EditSpec{OldStr: "foo", NewStr: "bar", AnchorBefore: "section B\n"}
+	modified, err := ApplyEdits(content, []EditSpec{edit})
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if string(modified) != "section A\nfoo\nsection B\nbar\n" {
+		t.Fatalf("unexpected result: %q", modified)
+	}
+}
+
+func TestApplyEditLineRangeOutOfBounds(t *testing.T) {
+	edit := EditSpec{OldStr: "x", NewStr: "y", LineRange: [2]int{5, 10}}
+	if _, err := ApplyEdits([]byte("a\nb\n"), []EditSpec{edit}); err == nil {
+		t.Fatal("expected an out-of-bounds line_range to error")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import "sync"
+
+// mimeCacheKey identifies a prior sniffContent result. It's scoped to a specific file
+// identity (mtime+size, not just path) so a stale cache entry can never outlive the
+// content it describes: any edit changes mtime or size (usually both) and misses.
+type mimeCacheKey struct {
+	uri       string
+	path      string
+	mtime     int64
+	size      int64
+	sniffSize int
+}
+
+var (
+	mimeCacheMu sync.Mutex
+	mimeCache   = map[mimeCacheKey]string{}
+)
+
+// mimeCacheLookup returns the cached raw (pre-suppression) sniff result for key, if any.
+func mimeCacheLookup(key mimeCacheKey) (string, bool) {
+	mimeCacheMu.Lock()
+	defer mimeCacheMu.Unlock()
+	mimeType, ok := mimeCache[key]
+	return mimeType, ok
+}
+
+func mimeCacheStore(key mimeCacheKey, mimeType string) {
+	mimeCacheMu.Lock()
+	defer mimeCacheMu.Unlock()
+	mimeCache[key] = mimeType
+}
@@ -0,0 +1,120 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MimePolicy restricts which files content pipelines (upload endpoints, the AI file-edit
+// tools, the block-view file previewer) are allowed to read or overwrite. It's loaded
+// straight from Wave's settings JSON, so the json tags are load-bearing.
+//
+// In deny mode (the default, Whitelist: false) MimeGlobs/Extensions name what to reject
+// and everything else is allowed. In whitelist mode only files matching MimeGlobs or
+// Extensions are allowed.
+type MimePolicy struct {
+	// MimeGlobs are path.Match-style patterns checked against the detected mime type,
+	// e.g. "text/*" or "application/x-executable".
+	MimeGlobs []string `json:"mimeGlobs,omitempty"`
+	// Extensions are file extensions, e.g. ".exe"; matched case-insensitively and
+	// normalized to include a leading dot.
+	Extensions []string `json:"extensions,omitempty"`
+	// Whitelist flips the policy from deny-listed to allow-listed.
+	Whitelist bool `json:"whitelist,omitempty"`
+}
+
+// ErrForbidden reports that a file was rejected by a MimePolicy, either because its mime
+// type or its extension matched (deny mode) or failed to match (whitelist mode).
+type ErrForbidden struct {
+	Kind  string // "mime" or "extension"
+	Value string
+}
+
+func (e *ErrForbidden) Error() string {
+	return fmt.Sprintf("%s %q is not allowed by policy", e.Kind, e.Value)
+}
+
+// Check evaluates filePath (and its already-known fs.FileInfo, or nil to stat it)
+// against the policy using local-disk detection. Use CheckFS to evaluate a file on a
+// remote pane or an in-memory test tree.
+func (p MimePolicy) Check(filePath string, fi fs.FileInfo) error {
+	return p.CheckFS(&BasicFS{}, filePath, fi)
+}
+
+// CheckFS is Check against an arbitrary FS.
+func (p MimePolicy) CheckFS(fsys FS, filePath string, fi fs.FileInfo) error {
+	ext := normalizeExt(strings.ToLower(filepath.Ext(filePath)))
+	// suppressOctetStream=false: a policy that explicitly names
+	// "application/octet-stream" should be able to match it, even though the public
+	// DetectMimeType discards that fallback as a detection failure.
+	mimeType, err := detectMimeTypeFS(fsys, filePath, fi, true, false, DetectMimeTypeOptions{})
+	if err != nil {
+		return fmt.Errorf("detecting mime type of %q: %w", filePath, err)
+	}
+
+	extMatch := p.matchesExtension(ext)
+	mimeMatch := mimeType != "" && p.matchesMimeGlob(mimeType)
+
+	if p.Whitelist {
+		if extMatch || mimeMatch {
+			return nil
+		}
+		if ext != "" {
+			return &ErrForbidden{Kind: "extension", Value: ext}
+		}
+		return &ErrForbidden{Kind: "mime", Value: mimeType}
+	}
+	if extMatch {
+		return &ErrForbidden{Kind: "extension", Value: ext}
+	}
+	if mimeMatch {
+		return &ErrForbidden{Kind: "mime", Value: mimeType}
+	}
+	return nil
+}
+
+func (p MimePolicy) matchesExtension(ext string) bool {
+	for _, candidate := range p.Extensions {
+		if normalizeExt(strings.ToLower(candidate)) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+func (p MimePolicy) matchesMimeGlob(mimeType string) bool {
+	// "application/octet-stream" means detection failed, not that the file is actually
+	// of that type, so a broad glob (e.g. "application/*") shouldn't catch it the way it
+	// would a real detected type. Only an exact, explicit listing opts a policy in.
+	if mimeType == "application/octet-stream" {
+		return p.matchesExactMime(mimeType)
+	}
+	for _, glob := range p.MimeGlobs {
+		if ok, err := path.Match(glob, mimeType); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p MimePolicy) matchesExactMime(mimeType string) bool {
+	for _, glob := range p.MimeGlobs {
+		if glob == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeExt(ext string) string {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		return "." + ext
+	}
+	return ext
+}
@@ -0,0 +1,97 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMimePolicyDenyModeBlocksListedExtension(t *testing.T) {
+	p := MimePolicy{Extensions: []string{"exe"}}
+	m := NewMemFS()
+	m.PutFile("/tool.exe", []byte("MZ"), 0644)
+
+	err := p.CheckFS(m, "/tool.exe", nil)
+	var forbidden *ErrForbidden
+	if !errors.As(err, &forbidden) || forbidden.Kind != "extension" {
+		t.Fatalf("expected an extension ErrForbidden, got %v", err)
+	}
+}
+
+func TestMimePolicyDenyModeAllowsUnlisted(t *testing.T) {
+	p := MimePolicy{Extensions: []string{".exe"}}
+	m := NewMemFS()
+	m.PutFile("/notes.txt", []byte("hello"), 0644)
+
+	if err := p.CheckFS(m, "/notes.txt", nil); err != nil {
+		t.Fatalf("expected no error for an unlisted extension, got %v", err)
+	}
+}
+
+func TestMimePolicyDenyModeBlocksMimeGlob(t *testing.T) {
+	p := MimePolicy{MimeGlobs: []string{"application/*"}}
+	m := NewMemFS()
+	m.PutFile("/data.json", []byte(`{}`), 0644)
+
+	err := p.CheckFS(m, "/data.json", nil)
+	var forbidden *ErrForbidden
+	if !errors.As(err, &forbidden) || forbidden.Kind != "mime" || forbidden.Value != "application/json" {
+		t.Fatalf("expected a mime ErrForbidden for application/json, got %v", err)
+	}
+}
+
+func TestMimePolicyWhitelistModeOnlyAllowsListed(t *testing.T) {
+	p := MimePolicy{Whitelist: true, MimeGlobs: []string{"text/*", "image/*"}}
+	m := NewMemFS()
+	m.PutFile("/readme.txt", []byte("hello"), 0644)
+	m.PutFile("/tool.exe", []byte("MZ"), 0644)
+
+	if err := p.CheckFS(m, "/readme.txt", nil); err != nil {
+		t.Fatalf("expected text/plain to be allowed, got %v", err)
+	}
+	if err := p.CheckFS(m, "/tool.exe", nil); err == nil {
+		t.Fatal("expected .exe to be rejected in whitelist mode")
+	}
+}
+
+func TestMimePolicyOctetStreamOnlyBlockedWhenExplicitlyListed(t *testing.T) {
+	// 512 bytes of high-entropy, non-UTF8 data that http.DetectContentType falls back
+	// to application/octet-stream for.
+	binary := make([]byte, 512)
+	for i := range binary {
+		binary[i] = byte(i % 251)
+	}
+	m := NewMemFS()
+	m.PutFile("/blob.bin", binary, 0644)
+
+	noPolicy := MimePolicy{}
+	if err := noPolicy.CheckFS(m, "/blob.bin", nil); err != nil {
+		t.Fatalf("expected an empty deny policy not to block an undetected type, got %v", err)
+	}
+
+	blockOctetStream := MimePolicy{MimeGlobs: []string{"application/octet-stream"}}
+	err := blockOctetStream.CheckFS(m, "/blob.bin", nil)
+	var forbidden *ErrForbidden
+	if !errors.As(err, &forbidden) || forbidden.Value != "application/octet-stream" {
+		t.Fatalf("expected application/octet-stream to be blocked once explicitly listed, got %v", err)
+	}
+}
+
+func TestMimePolicyOctetStreamNotCaughtByWildcardGlob(t *testing.T) {
+	// A broad glob like "application/*" names real, detected application/* types; it
+	// shouldn't also catch the "detection failed" fallback, only an exact, explicit
+	// "application/octet-stream" entry should.
+	binary := make([]byte, 512)
+	for i := range binary {
+		binary[i] = byte(i % 251)
+	}
+	m := NewMemFS()
+	m.PutFile("/blob.bin", binary, 0644)
+
+	p := MimePolicy{MimeGlobs: []string{"application/*"}}
+	if err := p.CheckFS(m, "/blob.bin", nil); err != nil {
+		t.Fatalf("expected a wildcard glob not to block an undetected type, got %v", err)
+	}
+}
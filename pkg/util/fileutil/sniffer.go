@@ -0,0 +1,174 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"unicode/utf8"
+)
+
+// DefaultSniffSize is how many bytes of file content detectMimeTypeFS reads for its
+// magic-number and text-format heuristics when the caller doesn't set
+// DetectMimeTypeOptions.SniffSize. It's bigger than the 512 bytes http.DetectContentType
+// looks at, so the text-format heuristics and the printable-ratio check see more of the
+// file. Parquet's trailing "PAR1" is not read from this buffer (see hasParquetTrailer).
+const DefaultSniffSize = 4096
+
+// magicSig is a file-signature match: content starting at Offset must equal Bytes.
+type magicSig struct {
+	Bytes  []byte
+	Offset int
+	Mime   string
+}
+
+// magicTable covers the binary formats http.DetectContentType doesn't know about.
+// Checked in order; the first match wins, so put longer/more specific signatures first.
+var magicTable = []magicSig{
+	{Bytes: []byte("SQLite format 3\x00"), Mime: "application/vnd.sqlite3"},
+	{Bytes: []byte("\x7fELF"), Mime: "application/x-elf"},
+	{Bytes: []byte("\x00asm"), Mime: "application/wasm"},
+	{Bytes: []byte("\xCA\xFE\xBA\xBE"), Mime: "application/x-mach-binary"}, // fat/universal
+	{Bytes: []byte("\xCF\xFA\xED\xFE"), Mime: "application/x-mach-binary"}, // 64-bit
+	{Bytes: []byte("\xCE\xFA\xED\xFE"), Mime: "application/x-mach-binary"}, // 32-bit
+	{Bytes: []byte("\x28\xB5\x2F\xFD"), Mime: "application/zstd"},
+	{Bytes: []byte("PAR1"), Mime: "application/vnd.apache.parquet"},
+}
+
+// sniffMagic matches buf against magicTable, plus the PE check (which needs the
+// "MZ"-relative PE header offset, not a fixed byte sequence) and a best-effort Parquet
+// trailer check (its format requires "PAR1" at both ends of the file, but we only have
+// the leading bytes in buf; fd lets us confirm the trailer when it supports io.ReaderAt).
+func sniffMagic(buf []byte, fd fs.File, fileInfo fs.FileInfo) string {
+	for _, sig := range magicTable {
+		if len(buf) < sig.Offset+len(sig.Bytes) {
+			continue
+		}
+		if bytes.Equal(buf[sig.Offset:sig.Offset+len(sig.Bytes)], sig.Bytes) {
+			if sig.Mime == "application/vnd.apache.parquet" && !hasParquetTrailer(fd, fileInfo) {
+				continue
+			}
+			return sig.Mime
+		}
+	}
+	if mime := sniffPE(buf); mime != "" {
+		return mime
+	}
+	return ""
+}
+
+// sniffPE recognizes a Windows PE executable: a "MZ" DOS header whose e_lfanew field (a
+// little-endian uint32 at offset 0x3C) points to a "PE\0\0" signature.
+func sniffPE(buf []byte) string {
+	if len(buf) < 0x40 || buf[0] != 'M' || buf[1] != 'Z' {
+		return ""
+	}
+	peOffset := int(buf[0x3C]) | int(buf[0x3D])<<8 | int(buf[0x3E])<<16 | int(buf[0x3F])<<24
+	if peOffset < 0 || peOffset+4 > len(buf) {
+		return ""
+	}
+	if bytes.Equal(buf[peOffset:peOffset+4], []byte("PE\x00\x00")) {
+		return "application/vnd.microsoft.portable-executable"
+	}
+	return ""
+}
+
+// hasParquetTrailer confirms the "PAR1" magic also appears in the file's last 4 bytes, as
+// the Parquet format requires. It's a best-effort check: fd must support io.ReaderAt (not
+// guaranteed by fs.File), and it's skipped (treated as a match) when it doesn't, so a
+// leading-magic match still counts on FS backends that can't seek.
+func hasParquetTrailer(fd fs.File, fileInfo fs.FileInfo) bool {
+	ra, ok := fd.(io.ReaderAt)
+	if !ok || fileInfo == nil || fileInfo.Size() < 8 {
+		return true
+	}
+	trailer := make([]byte, 4)
+	if _, err := ra.ReadAt(trailer, fileInfo.Size()-4); err != nil {
+		return true
+	}
+	return bytes.Equal(trailer, []byte("PAR1"))
+}
+
+// sniffText distinguishes common structured-text formats by peeking the first
+// non-whitespace byte (and, for JSON vs JSONL, whether more than one top-level object is
+// present). It's only a heuristic fallback for files the extension map and
+// http.DetectContentType didn't identify.
+func sniffText(buf []byte) string {
+	trimmed := bytes.TrimLeft(buf, " \t\r\n")
+	if len(trimmed) == 0 {
+		return ""
+	}
+	switch trimmed[0] {
+	case '{', '[':
+		if looksLikeJSONL(trimmed) {
+			return "application/jsonl"
+		}
+		return "application/json"
+	case '-':
+		if bytes.HasPrefix(trimmed, []byte("---")) {
+			return "application/x-yaml"
+		}
+	}
+	return ""
+}
+
+// looksLikeJSONL reports whether trimmed looks like newline-delimited JSON (one JSON
+// value per line) rather than a single JSON document: at least two non-blank lines, each
+// starting with '{' or '['.
+func looksLikeJSONL(trimmed []byte) bool {
+	lines := bytes.Split(trimmed, []byte("\n"))
+	objLines := 0
+	for _, line := range lines {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] != '{' && line[0] != '[' {
+			return false
+		}
+		objLines++
+	}
+	return objLines >= 2
+}
+
+// looksLikeText applies the same signal a text editor's "binary file" heuristic would: a
+// UTF-8/UTF-16 byte-order mark is conclusive; otherwise buf must contain no NUL bytes and
+// be mostly printable/whitespace runes.
+func looksLikeText(buf []byte) bool {
+	if hasTextBOM(buf) {
+		return true
+	}
+	if bytes.IndexByte(buf, 0) >= 0 {
+		return false
+	}
+	printable, total := 0, 0
+	for len(buf) > 0 {
+		r, size := utf8.DecodeRune(buf)
+		if r == utf8.RuneError && size <= 1 {
+			return false
+		}
+		total++
+		if r == '\t' || r == '\n' || r == '\r' || (r >= 0x20 && r != 0x7f) {
+			printable++
+		}
+		buf = buf[size:]
+	}
+	if total == 0 {
+		return false
+	}
+	return float64(printable)/float64(total) > 0.95
+}
+
+func hasTextBOM(buf []byte) bool {
+	switch {
+	case bytes.HasPrefix(buf, []byte{0xEF, 0xBB, 0xBF}): // UTF-8
+		return true
+	case bytes.HasPrefix(buf, []byte{0xFE, 0xFF}): // UTF-16 BE
+		return true
+	case bytes.HasPrefix(buf, []byte{0xFF, 0xFE}): // UTF-16 LE
+		return true
+	}
+	return false
+}
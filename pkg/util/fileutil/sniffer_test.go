@@ -0,0 +1,101 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileutil
+
+import "testing"
+
+func TestDetectMimeTypeFSSniffsMagicNumbers(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"elf", append([]byte("\x7fELF\x02\x01\x01\x00"), make([]byte, 64)...), "application/x-elf"},
+		{"wasm", append([]byte("\x00asm\x01\x00\x00\x00"), make([]byte, 64)...), "application/wasm"},
+		{"sqlite", append([]byte("SQLite format 3\x00"), make([]byte, 64)...), "application/vnd.sqlite3"},
+		{"zstd", append([]byte("\x28\xB5\x2F\xFD"), make([]byte, 64)...), "application/zstd"},
+		{"parquet", append([]byte("PAR1"), make([]byte, 64)...), "application/vnd.apache.parquet"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := NewMemFS()
+			m.PutFile("/bin/"+c.name, c.data, 0755)
+			got := DetectMimeTypeFS(m, "/bin/"+c.name, nil, true)
+			if got != c.want {
+				t.Fatalf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestDetectMimeTypeFSSniffsPE(t *testing.T) {
+	buf := make([]byte, 128)
+	copy(buf, "MZ")
+	// e_lfanew at 0x3C points at offset 0x40, where the "PE\0\0" signature lives.
+	buf[0x3C] = 0x40
+	copy(buf[0x40:], "PE\x00\x00")
+	// No extension: ".exe" and ".bin" are both already in StaticMimeTypeMap, which
+	// would shortcut detectMimeTypeFS before the content-sniffing path (and sniffPE)
+	// ever runs.
+	m := NewMemFS()
+	m.PutFile("/bin/app", buf, 0755)
+	got := DetectMimeTypeFS(m, "/bin/app", nil, true)
+	if got != "application/vnd.microsoft.portable-executable" {
+		t.Fatalf("expected PE mime type, got %q", got)
+	}
+}
+
+func TestDetectMimeTypeFSSniffsJSONLOverJSON(t *testing.T) {
+	// No extension, so the extension map doesn't shortcut detection before the content
+	// heuristics run; http.DetectContentType itself only gets as far as generic
+	// "text/plain", so sniffText has to recognize the JSONL shape from the content.
+	m := NewMemFS()
+	m.PutFile("/data/events", []byte(`{"a":1}`+"\n"+`{"a":2}`+"\n"), 0644)
+	got := DetectMimeTypeFS(m, "/data/events", nil, true)
+	if got != "application/jsonl" {
+		t.Fatalf("expected application/jsonl, got %q", got)
+	}
+}
+
+func TestDetectMimeTypeFSSniffsYAML(t *testing.T) {
+	m := NewMemFS()
+	m.PutFile("/data/config", []byte("---\nname: test\n"), 0644)
+	got := DetectMimeTypeFS(m, "/data/config", nil, true)
+	if got != "application/x-yaml" {
+		t.Fatalf("expected application/x-yaml, got %q", got)
+	}
+}
+
+func TestDetectMimeTypeFSFallsBackToTextPlainForControlBytes(t *testing.T) {
+	// A stray vertical-tab byte (0x0B) is enough to make http.DetectContentType call
+	// this binary, even though it's otherwise ordinary, overwhelmingly printable prose;
+	// only the printable-ratio fallback recognizes it as text.
+	content := []byte("normal readable english prose that is long enough to pad out \x0b past the sniff window's minimum, more text follows after the control byte")
+	m := NewMemFS()
+	m.PutFile("/data/readme", content, 0644)
+	got := DetectMimeTypeFS(m, "/data/readme", nil, true)
+	if got != "text/plain" {
+		t.Fatalf("expected text/plain, got %q", got)
+	}
+}
+
+func TestDetectMimeTypeFSCachesSniffResult(t *testing.T) {
+	m := NewMemFS()
+	m.PutFile("/bin/cached", append([]byte("\x00asm\x01\x00\x00\x00"), make([]byte, 64)...), 0755)
+	fi, err := m.Stat("/bin/cached")
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	key := mimeCacheKey{uri: m.URI(), path: "/bin/cached", mtime: fi.ModTime().UnixNano(), size: fi.Size(), sniffSize: DefaultSniffSize}
+	if _, ok := mimeCacheLookup(key); ok {
+		t.Fatal("cache should be empty before the first detection")
+	}
+	if got := DetectMimeTypeFS(m, "/bin/cached", nil, true); got != "application/wasm" {
+		t.Fatalf("expected application/wasm, got %q", got)
+	}
+	cached, ok := mimeCacheLookup(key)
+	if !ok || cached != "application/wasm" {
+		t.Fatalf("expected the sniff result to be cached, got %q, ok=%v", cached, ok)
+	}
+}
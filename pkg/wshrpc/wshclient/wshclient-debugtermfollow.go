@@ -0,0 +1,17 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package wshclient
+
+import (
+	"github.com/wavetermdev/waveterm/pkg/wshrpc"
+	"github.com/wavetermdev/waveterm/pkg/wshutil"
+)
+
+// DebugTermFollowCommand subscribes to a live stream of data.BlockId's terminal output.
+// Unlike DebugTermCommand's single snapshot, the returned channel stays open (closed by
+// the server when ctx/opts.Timeout ends the subscription, or by the caller ranging out
+// of it), delivering one CommandDebugTermFollowRtnData per chunk as it's produced.
+func DebugTermFollowCommand(w *wshutil.WshRpc, data wshrpc.CommandDebugTermFollowData, opts *wshrpc.RpcOpts) (chan wshrpc.CommandDebugTermFollowRtnData, error) {
+	return sendRpcRequestResponseStreamHelper[wshrpc.CommandDebugTermFollowRtnData](w, wshrpc.Command_DebugTermFollow, data, opts)
+}
@@ -0,0 +1,16 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package wshclient
+
+import (
+	"github.com/wavetermdev/waveterm/pkg/wshrpc"
+	"github.com/wavetermdev/waveterm/pkg/wshutil"
+)
+
+// TermWriteDataCommand writes data.Data into data.BlockId's terminal PTY, the same RPC
+// plumbing DebugTermCommand uses for reads, just in the opposite direction.
+func TermWriteDataCommand(w *wshutil.WshRpc, data wshrpc.CommandTermWriteData, opts *wshrpc.RpcOpts) error {
+	_, err := sendRpcRequestCallHelper[any](w, wshrpc.Command_TermWriteData, data, opts)
+	return err
+}
@@ -0,0 +1,26 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package wshrpc
+
+// Command_DebugTermFollow is the RPC method name for CommandDebugTermFollowData,
+// dispatched server-side to termevents.HandleDebugTermFollow. Unlike most wshrpc
+// commands, it returns a stream of CommandDebugTermFollowRtnData rather than a single
+// response, since it tails a block's terminal output live.
+const Command_DebugTermFollow = "debugtermfollow"
+
+// CommandDebugTermFollowData subscribes to a live stream of BlockId's terminal output.
+// SinceTs/UntilTs (unix milliseconds) carry the caller's time bounds; 0 means unbounded.
+// termevents.HandleDebugTermFollow does not apply them: it replays the whole buffered
+// history before the stream goes live.
+type CommandDebugTermFollowData struct {
+	BlockId string `json:"blockid"`
+	SinceTs int64  `json:"sincets,omitempty"`
+	UntilTs int64  `json:"untilts,omitempty"`
+}
+
+// CommandDebugTermFollowRtnData is one streamed chunk of a followed block's terminal
+// output, base64-encoded the same way CommandDebugTermRtnData.Data64 is.
+type CommandDebugTermFollowRtnData struct {
+	Data64 string `json:"data64"`
+}
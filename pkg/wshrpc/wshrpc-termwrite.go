@@ -0,0 +1,16 @@
+// Copyright 2026, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package wshrpc
+
+// Command_TermWriteData is the RPC method name for CommandTermWriteData, dispatched
+// server-side to termevents.HandleTermWriteData.
+const Command_TermWriteData = "termwritedata"
+
+// CommandTermWriteData requests that Data be written into BlockId's terminal PTY, as if
+// it had arrived from the terminal process itself. termplay uses this to replay a
+// recorded asciicast session's output events into a live block.
+type CommandTermWriteData struct {
+	BlockId string `json:"blockid"`
+	Data    []byte `json:"data"`
+}